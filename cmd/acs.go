@@ -1,112 +1,397 @@
 package cmd
 
 import (
-	"fmt"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
-// parseAcs processes the acceptance criteria text and generates test blocks
-func parseAcs(acsText string) string {
-	lines := strings.Split(acsText, "\n")
-	var result strings.Builder
+// Placeholder in the template header replaced by the ACs link
+const acsLinkPlaceholder = "TODO: Link ACs tickets here"
 
-	level1Regex := regexp.MustCompile(`^\s*(\d+)\.\s+(.+)$`)
-	level2Regex := regexp.MustCompile(`^\s*([a-z])\.\s+(.+)$`)
-	level3Regex := regexp.MustCompile(`^\s*([ivx]+)\.\s+(.+)$`)
+// parseAcText parses ACs in any of the supported syntaxes, chosen from the
+// file name when there is one and from the content otherwise
+func parseAcText(fileName, text string, config acsConfig) acDocument {
+	switch ext := strings.ToLower(filepath.Ext(fileName)); {
+	case ext == ".docx":
+		return parseDocx([]byte(text), config)
+	case ext == ".feature", isGherkin(text):
+		return parseGherkin(text)
+	case isJiraIssue(text):
+		return parseJiraIssue(text, config)
+	case (ext == "" || ext == ".yaml" || ext == ".yml" || ext == ".json") && isStructuredAcs(text):
+		return parseStructuredAcs(text)
+	case ext == ".csv", ext == ".tsv":
+		return parseCSV(text, config.CSV)
+	case ext == ".html", ext == ".htm", isHTML(text):
+		return parseHTML(text, config)
+	case ext == ".md", ext == ".markdown", isMarkdown(text):
+		return parseMarkdown(text, config)
+	}
+	return parseAcOutline(text, config)
+}
 
-	// Keep track of the current context
-	var currentLevel1, currentLevel2 string
-	indentLevel := 0
+// renderAcs prints the test blocks for an AC tree
+func renderAcs(nodes []*acNode, settings specSettings) string {
+	return printNodes(settings.format, 1, separated(renderAcNodes(nodes, settings)...))
+}
 
-	for _, line := range lines {
-		trimmedLine := strings.TrimSpace(line)
-		if trimmedLine == "" {
-			continue
-		}
+// acsTestBlock creates the stub test generated for a single criterion, with
+// its notes and steps as comments above the TODO and the hash of the
+// criterion after it, so that sync can tell when the criterion changes
+func acsTestBlock(node *acNode, settings specSettings) tsNode {
+	test := tsTestBlock(acsCallee("it", node.Skip, settings), acsTestTitle(node, settings), true, acsTestBody(node, settings)...)
+	return tsTrailingComment{test, acHashComment(node)}
+}
+
+// acsCallee returns the describe or it function to call, the skipped
+// variant for skipped criteria
+func acsCallee(callee string, skip bool, settings specSettings) string {
+	switch {
+	case !skip:
+		return callee
+	case settings.framework.usesJasmine():
+		return "x" + callee
+	default:
+		return callee + ".skip"
+	}
+}
+
+// acsTestTitle returns the title of the test for a criterion, prefixed with
+// its test case ID unless IDs go in comments and followed by its tags
+func acsTestTitle(node *acNode, settings specSettings) string {
+	title := normalizeTestTitle(node.Title, settings)
+	if node.ID != "" && settings.config.Acs.IDs != idsInComment {
+		title = "[" + node.ID + "] " + title
+	}
+	return strings.Join(append([]string{title}, node.Tags...), " ")
+}
+
+// acsDescribeTitle returns the title of the describe block for a group,
+// prefixed with its ID like the titles of tests
+func acsDescribeTitle(node *acNode, settings specSettings) string {
+	title := groupTitle(node.Title)
+	if node.ID != "" && settings.config.Acs.IDs != idsInComment {
+		title = "[" + node.ID + "] " + title
+	}
+	return strings.Join(append([]string{title}, node.Tags...), " ")
+}
+
+func acsTestBody(node *acNode, settings specSettings) []tsNode {
+	var body []tsNode
+	if node.ID != "" && settings.config.Acs.IDs == idsInComment {
+		body = append(body, tsComment(node.ID))
+	}
+	var mounted tsExpr = tsAwait{tsCall{"mount", nil}}
+	if settings.mounted != "" {
+		mounted = tsRaw(settings.mounted)
+	}
+	body = append(body, tsConst{"{ " + strings.Join(mountResults, ", ") + " }", mounted})
+	body = append(body, acsComments(node)...)
+	body = append(body, tsComment("TODO: Implement test"))
+	return append(body, acsSnippets(node.Title, settings.config.Snippets, settings.framework)...)
+}
+
+// acsSetupBlock creates the beforeEach hook for a Gherkin background
+func acsSetupBlock(node *acNode) tsNode {
+	body := acsComments(node)
+	if len(body) > 0 {
+		// The hook has no mount call for the comments to be separated from
+		body = body[1:]
+	}
+	body = append(body, tsComment("TODO: Implement setup"))
+
+	return tsTestBlock("beforeEach", "", true, body...)
+}
+
+// Precondition phrasing of describe-level criteria, such as "Given the user
+// is an admin", "With an empty cart" or "When the user is logged out"
+var preconditionRegex = regexp.MustCompile(`(?i)^(?:given|with|without|as an?|(?:when|while|if) (?:the |a |an )?\w+ (?:is|are|has|have))\s`)
+
+// Variable the beforeEach of a precondition describe mounts the component into
+const acsMountedVariable = "mounted"
 
-		if matches := level1Regex.FindStringSubmatch(trimmedLine); len(matches) > 0 {
-			// Close previous blocks if any
-			if currentLevel2 != "" {
-				result.WriteString(getIndentation(1) + "});\n\n")
-				currentLevel2 = ""
+// acsPreconditions returns the setup a group describes: its title when it is
+// phrased as a precondition and the preconditions listed under it
+func acsPreconditions(node *acNode) []string {
+	var preconditions []string
+	if title := groupTitle(node.Title); preconditionRegex.MatchString(title) {
+		preconditions = append(preconditions, title)
+	}
+	return append(preconditions, node.Preconditions...)
+}
+
+// acsMountBlock creates the beforeEach hook of a precondition describe, which
+// mounts the component once the preconditions are set up, and returns the
+// settings for the tests inside it. Nested preconditions reuse the component
+// mounted by the outer hook.
+func acsMountBlock(preconditions []string, settings specSettings) ([]tsNode, specSettings) {
+	body := []tsNode{tsComment(strings.Join(preconditions, "\n")), tsComment("TODO: Implement setup")}
+	if settings.mounted != "" {
+		return []tsNode{tsTestBlock("beforeEach", "", true, body...)}, settings
+	}
+
+	body = append(body, tsExprStatement{tsRaw(acsMountedVariable + " = await mount()")})
+	settings.mounted = acsMountedVariable
+	return []tsNode{
+		tsLet{acsMountedVariable, "Awaited<ReturnType<typeof mount>>"},
+		tsTestBlock("beforeEach", "", true, body...),
+	}, settings
+}
+
+// acsEachBlock creates a test run once per example row, with it.each or, for
+// Jasmine which has no it.each, a forEach loop around the test
+func acsEachBlock(node *acNode, settings specSettings) tsNode {
+	if !acsHasPlaceholders(node) {
+		return acsTableBlock(node, settings)
+	}
+
+	params := exampleIdentifiers(node.Examples.Header)
+	names := make(map[string]string)
+	for i, column := range node.Examples.Header {
+		if _, ok := names[column]; !ok {
+			names[column] = params[i]
+		}
+	}
+	var rows tsArray
+	for _, row := range node.Examples.Rows {
+		var properties []tsProperty
+		for i, value := range row {
+			if i < len(params) {
+				properties = append(properties, tsProperty{key: params[i], value: tsString(value)})
 			}
-			if currentLevel1 != "" {
-				result.WriteString("});\n\n")
+		}
+		rows = append(rows, tsObject{properties: properties, inline: true})
+	}
+	destructured := "{ " + strings.Join(params, ", ") + " }"
+
+	// Placeholders such as <email> become $email for it.each and ${email} in
+	// template literals
+	title := acsTestTitle(node, settings)
+	placeholders := examplePlaceholderRegex.FindAllStringSubmatchIndex(title, -1)
+
+	if settings.framework.usesJasmine() {
+		var parts tsTemplate
+		last := 0
+		for _, match := range placeholders {
+			name, ok := names[title[match[2]:match[3]]]
+			if !ok {
+				continue
 			}
+			parts = append(parts, title[last:match[0]], name)
+			last = match[1]
+		}
+		parts = append(parts, title[last:])
+
+		test := tsExprStatement{tsCall{acsCallee("it", node.Skip, settings), []tsExpr{parts, tsArrow{async: true, body: acsTestBody(node, settings)}}}}
+		return tsExprStatement{tsChain{rows, "forEach", []tsExpr{tsArrow{params: destructured, body: []tsNode{tsTrailingComment{test, acHashComment(node)}}}}}}
+	}
+
+	title = examplePlaceholderRegex.ReplaceAllStringFunc(title, func(placeholder string) string {
+		if name, ok := names[placeholder[1:len(placeholder)-1]]; ok {
+			return "$" + name
+		}
+		return placeholder
+	})
+
+	each := tsCall{acsCallee("it", node.Skip, settings) + ".each", []tsExpr{rows}}
+	test := tsExprStatement{tsChain{each, "", []tsExpr{tsString(title), tsArrow{async: true, params: destructured, body: acsTestBody(node, settings)}}}}
+	return tsTrailingComment{test, acHashComment(node)}
+}
+
+var examplePlaceholderRegex = regexp.MustCompile(`<([^<>]+)>`)
+
+// acsHasPlaceholders reports whether the title of a criterion names columns
+// of its examples, as Gherkin scenario outlines do
+func acsHasPlaceholders(node *acNode) bool {
+	for _, match := range examplePlaceholderRegex.FindAllStringSubmatch(node.Title, -1) {
+		if slices.Contains(node.Examples.Header, match[1]) {
+			return true
+		}
+	}
+	return false
+}
 
-			// Start new level 1 block
-			title := sanitizeTitle(matches[2])
-			result.WriteString(fmt.Sprintf("describe('%s', () => {\n", title))
-			currentLevel1 = title
-			indentLevel = 1
-
-		} else if matches := level2Regex.FindStringSubmatch(trimmedLine); len(matches) > 0 {
-			title := sanitizeTitle(matches[2])
-
-			if strings.Contains(title, "describe") {
-				if currentLevel2 != "" {
-					result.WriteString(getIndentation(1) + "});\n\n")
-				}
-
-				describeRegex := regexp.MustCompile(`\(describe\s*\(\s*["']([^"']*)["']\s*\)\)`)
-				describeMatches := describeRegex.FindStringSubmatch(title)
-
-				var describeTitle string
-				if len(describeMatches) >= 2 {
-					describeTitle = describeMatches[1]
-				} else {
-					// If no match, use the text before "describe"
-					parts := strings.Split(title, "(describe")
-					describeTitle = strings.TrimSpace(parts[0])
-				}
-
-				result.WriteString(fmt.Sprintf("%sdescribe('%s', () => {\n", getIndentation(indentLevel), describeTitle))
-				currentLevel2 = describeTitle
-				indentLevel = 2
-			} else {
-				result.WriteString(fmt.Sprintf("%sit('should %s', async () => {\n",
-					getIndentation(indentLevel),
-					lcFirst(title)))
-				result.WriteString(fmt.Sprintf("%sconst { view, httpTestingController, loader } = await mount();\n", getIndentation(indentLevel+1)))
-				result.WriteString(fmt.Sprintf("%s// TODO: Implement test\n", getIndentation(indentLevel+1)))
-				result.WriteString(fmt.Sprintf("%s});\n\n", getIndentation(indentLevel)))
+// acsTableBlock creates a test run once per row of a table whose columns the
+// title doesn't name. The rows are typed tuples and the values are listed
+// after the title.
+func acsTableBlock(node *acNode, settings specSettings) tsNode {
+	params := exampleIdentifiers(node.Examples.Header)
+	var types []string
+	for i := range node.Examples.Header {
+		types = append(types, tableColumnType(node.Examples.Rows, i))
+	}
+	tuple := "[" + strings.Join(types, ", ") + "]"
+
+	var rows tsArray
+	for _, row := range node.Examples.Rows {
+		var values tsTuple
+		for i, typ := range types {
+			value := ""
+			if i < len(row) {
+				value = row[i]
 			}
+			values = append(values, tableValue(value, typ))
+		}
+		rows = append(rows, values)
+	}
 
-		} else if matches := level3Regex.FindStringSubmatch(trimmedLine); len(matches) > 0 {
-			title := sanitizeTitle(matches[2])
-			result.WriteString(fmt.Sprintf("%sit('should %s', async () => {\n",
-				getIndentation(indentLevel),
-				lcFirst(title)))
-			result.WriteString(fmt.Sprintf("%sconst { view, httpTestingController, loader } = await mount();\n", getIndentation(indentLevel+1)))
-			result.WriteString(fmt.Sprintf("%s// TODO: Implement test\n", getIndentation(indentLevel+1)))
-			result.WriteString(fmt.Sprintf("%s});\n\n", getIndentation(indentLevel)))
+	title := acsTestTitle(node, settings)
+	body := acsTestBody(node, settings)
+
+	if settings.framework.usesJasmine() {
+		parts := tsTemplate{title + " ("}
+		for i, column := range node.Examples.Header {
+			if i > 0 {
+				parts[len(parts)-1] += ", "
+			}
+			parts[len(parts)-1] += column + ": "
+			parts = append(parts, params[i], "")
 		}
+		parts[len(parts)-1] += ")"
+
+		test := tsExprStatement{tsCall{acsCallee("it", node.Skip, settings), []tsExpr{parts, tsArrow{async: true, body: body}}}}
+		loop := tsChain{tsCast{rows, tuple + "[]"}, "forEach", []tsExpr{tsArrow{params: "[" + strings.Join(params, ", ") + "]", body: []tsNode{tsTrailingComment{test, acHashComment(node)}}}}}
+		return tsExprStatement{loop}
 	}
 
-	// Close any open blocks
-	if currentLevel2 != "" {
-		result.WriteString(getIndentation(1) + "});\n\n")
+	each := tsCall{acsCallee("it", node.Skip, settings) + ".each<" + tuple + ">", []tsExpr{rows}}
+	test := tsExprStatement{tsChain{each, "", []tsExpr{tsString(acsTableTitle(node, settings)), tsArrow{async: true, params: strings.Join(params, ", "), body: body}}}}
+	return tsTrailingComment{test, acHashComment(node)}
+}
+
+// acsTableTitle returns the title of a test run with it.each over a table,
+// the values of each row formatted with %s after the criterion
+func acsTableTitle(node *acNode, settings specSettings) string {
+	var values []string
+	for _, column := range node.Examples.Header {
+		values = append(values, strings.ReplaceAll(column, "%", "%%")+": %s")
 	}
-	if currentLevel1 != "" {
-		result.WriteString("});\n")
+	return strings.ReplaceAll(acsTestTitle(node, settings), "%", "%%") + " (" + strings.Join(values, ", ") + ")"
+}
+
+// acsTestName returns the title of the test generated for a criterion as it
+// reads in the spec
+func acsTestName(node *acNode, settings specSettings) string {
+	if node.Examples != nil && len(node.Examples.Rows) > 0 && !acsHasPlaceholders(node) {
+		return acsTableTitle(node, settings)
 	}
+	return acsTestTitle(node, settings)
+}
 
-	return result.String()
+// exampleIdentifiers turns the columns of a table into the names of the
+// parameters of its test, numbering columns that would share a name
+func exampleIdentifiers(header []string) []string {
+	used := make(map[string]bool)
+	var identifiers []string
+	for _, column := range header {
+		base := exampleIdentifier(column)
+		identifier := base
+		for n := 2; used[identifier]; n++ {
+			identifier = base + strconv.Itoa(n)
+		}
+		used[identifier] = true
+		identifiers = append(identifiers, identifier)
+	}
+	return identifiers
 }
 
-func getIndentation(level int) string {
-	return strings.Repeat("\t", level)
+// Words that can't name a parameter
+var tsReservedWords = []string{
+	"arguments", "await", "break", "case", "catch", "class", "const", "continue", "debugger", "default",
+	"delete", "do", "else", "enum", "eval", "export", "extends", "false", "finally", "for", "function",
+	"if", "implements", "import", "in", "instanceof", "interface", "let", "new", "null", "package",
+	"private", "protected", "public", "return", "static", "super", "switch", "this", "throw", "true",
+	"try", "typeof", "var", "void", "while", "with", "yield",
 }
 
-func sanitizeTitle(title string) string {
-	if strings.Contains(strings.ToLower(title), "describe") {
-		return title
+// exampleIdentifier turns an examples column such as "first name" into a
+// valid identifier, firstName
+func exampleIdentifier(column string) string {
+	var result strings.Builder
+	for i, word := range strings.FieldsFunc(column, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '$'
+	}) {
+		if i == 0 {
+			result.WriteString(lcFirst(word))
+		} else {
+			result.WriteString(ucFirst(word))
+		}
 	}
 
-	title = strings.TrimSpace(title)
-	return title
+	identifier := result.String()
+	if first, _ := utf8.DecodeRuneInString(identifier); identifier == "" || unicode.IsDigit(first) {
+		identifier = "_" + identifier
+	}
+	if slices.Contains(tsReservedWords, identifier) {
+		identifier += "_"
+	}
+	return identifier
+}
+
+// acsComments returns a node's notes and its steps grouped into Arrange, Act
+// and Assert sections, each preceded by a blank line
+func acsComments(node *acNode) []tsNode {
+	var comments []tsNode
+	if node.Priority != "" {
+		comments = append(comments, tsBlank{}, tsComment("Priority: "+node.Priority))
+	}
+	if len(node.Notes) > 0 {
+		comments = append(comments, tsBlank{}, tsComment(strings.Join(node.Notes, "\n")))
+	}
+	if len(node.Preconditions) > 0 {
+		comments = append(comments, tsBlank{}, tsComment("Preconditions\n"+strings.Join(node.Preconditions, "\n")))
+	}
+
+	section := ""
+	var lines []string
+	flush := func() {
+		if len(lines) > 0 {
+			comments = append(comments, tsBlank{}, tsComment(section+"\n"+strings.Join(lines, "\n")))
+		}
+		lines = nil
+	}
+
+	for _, step := range node.Steps {
+		if next := stepSection(step.Keyword); next != "" && next != section {
+			flush()
+			section = next
+		}
+		if section == "" {
+			section = "Arrange"
+		}
+		lines = append(lines, step.line())
+	}
+	flush()
+
+	return comments
+}
+
+// stepSection returns the test section a step keyword starts, or "" for
+// keywords such as And that continue the current one
+func stepSection(keyword string) string {
+	switch keyword {
+	case "Given":
+		return "Arrange"
+	case "When", stepAction:
+		return "Act"
+	case "Then", stepExpected:
+		return "Assert"
+	}
+	return ""
+}
+
+// line returns the step as written in the test comments. Imported steps are
+// only labelled by their section.
+func (s acStep) line() string {
+	if s.Keyword == stepAction || s.Keyword == stepExpected {
+		return s.Text
+	}
+	return s.Keyword + " " + s.Text
 }
 
 func lcFirst(s string) string {
@@ -118,17 +403,33 @@ func lcFirst(s string) string {
 	return string(r)
 }
 
+func ucFirst(s string) string {
+	if s == "" {
+		return ""
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
 func integrateAcsWithTemplate(templateContent, acsLink, acsBlocks string) string {
-	endBlock := "});"
+	content := strings.TrimSpace(templateContent)
+
+	// The template may be formatted with or without semicolons
+	endBlock := "})"
+	if strings.HasSuffix(content, "});") {
+		endBlock = "});"
+	}
 
-	content := strings.TrimSuffix(strings.TrimSpace(templateContent), endBlock)
+	content = strings.TrimSuffix(content, endBlock)
 
 	if acsLink != "" {
-		content = strings.Replace(content, "TODO: Link ACs tickets here", acsLink, 1)
+		acsLink = escapeJsDoc(acsLink)
+		content = strings.Replace(content, acsLinkPlaceholder, acsLink, 1)
 	}
 
 	content += "\n" + acsBlocks
-	content += endBlock
+	content += endBlock + "\n"
 
 	return content
 }
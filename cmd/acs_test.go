@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderAcsEscapesTitles(t *testing.T) {
+	document := parseAcText("", "1. Saving 'drafts'\na. Shows the user's drafts\nb. Path C:\\temp is rejected", acsConfig{})
+	result := renderAcs(document.Nodes, defaultSpecSettings().forAcs(document))
+
+	expectedPhrases := []string{
+		`describe("Saving 'drafts'", () => {`,
+		`it("should show the user's drafts", async () => {`,
+		`it('should path C:\\temp is rejected', async () => {`,
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("renderAcs() does not contain expected phrase %q in:\n%s", phrase, result)
+		}
+	}
+}
+
+func TestIntegrateAcsWithTemplateEscapesLink(t *testing.T) {
+	template := createTemplate("user", defaultSpecSettings())
+	result := integrateAcsWithTemplate(template, "https://example.com/*/JIRA-1", "")
+
+	if !strings.Contains(result, " - https://example.com/*\\/JIRA-1") {
+		t.Errorf("integrateAcsWithTemplate() should escape the comment terminator, got:\n%s", result)
+	}
+}
+
+func TestRenderAcsPreconditions(t *testing.T) {
+	tests := []struct {
+		name       string
+		acs        string
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name: "Given",
+			acs:  "1. Given the user is an admin\na. Sees the settings",
+			expected: []string{
+				"\tdescribe('Given the user is an admin', () => {\n\t\tlet mounted: Awaited<ReturnType<typeof mount>>;\n\n",
+				"\t\tbeforeEach(async () => {\n\t\t\t// Given the user is an admin\n\t\t\t// TODO: Implement setup\n\t\t\tmounted = await mount();\n\t\t});\n",
+				"\t\t\tconst { view, httpTestingController, loader } = mounted;\n",
+			},
+			unexpected: []string{"await mount();\n\t\t\t// TODO: Implement test"},
+		},
+		{
+			name: "When the user is",
+			acs:  "1. When the user is logged out\na. Sees the login form",
+			expected: []string{
+				"// When the user is logged out\n",
+				"const { view, httpTestingController, loader } = mounted;",
+			},
+		},
+		{
+			name: "Nested precondition",
+			acs:  "1. With an empty cart\na. Without a coupon\ni. Shows the total",
+			expected: []string{
+				"\n\t\t\tbeforeEach(async () => {\n\t\t\t\t// Without a coupon\n\t\t\t\t// TODO: Implement setup\n\t\t\t});\n",
+				"\t\t\t\tconst { view, httpTestingController, loader } = mounted;\n",
+			},
+		},
+		{
+			name:       "Action",
+			acs:        "1. When the user clicks save\na. Shows a message",
+			expected:   []string{"const { view, httpTestingController, loader } = await mount();"},
+			unexpected: []string{"beforeEach", "mounted"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document := parseAcText("", tt.acs, acsConfig{})
+			result := renderAcs(document.Nodes, defaultSpecSettings().forAcs(document))
+
+			for _, phrase := range tt.expected {
+				if !strings.Contains(result, phrase) {
+					t.Errorf("renderAcs() does not contain expected phrase %q in:\n%s", phrase, result)
+				}
+			}
+			for _, phrase := range tt.unexpected {
+				if strings.Contains(result, phrase) {
+					t.Errorf("renderAcs() should not contain %q in:\n%s", phrase, result)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderAcsTables(t *testing.T) {
+	acs := "1. Applies the discount\n| quantity | total | member |\n|---|---|---|\n| 1 | 10.5 | true |\n| 2 | 20 | false |"
+
+	noSemi := defaultSpecSettings().format
+	noSemi.semi = false
+
+	tests := []struct {
+		name      string
+		framework testFramework
+		format    *formatOptions
+		expected  []string
+	}{
+		{
+			name:      "Jest",
+			framework: frameworkJest,
+			expected: []string{
+				"\tit.each<[number, number, boolean]>([\n\t\t[1, 10.5, true],\n\t\t[2, 20, false],\n\t])(",
+				"('should apply the discount (quantity: %s, total: %s, member: %s)', async (quantity, total, member) => {\n",
+			},
+		},
+		{
+			name:      "Jasmine",
+			framework: frameworkKarma,
+			expected: []string{
+				"\t([\n\t\t[1, 10.5, true],\n\t\t[2, 20, false],\n\t] as [number, number, boolean][]).forEach(([quantity, total, member]) => {\n",
+				"\t\tit(`should apply the discount (quantity: ${quantity}, total: ${total}, member: ${member})`, async () => {\n",
+			},
+		},
+		{
+			name:      "Jasmine without semicolons",
+			framework: frameworkKarma,
+			format:    &noSemi,
+			expected: []string{
+				"\t;([\n\t\t[1, 10.5, true],\n\t\t[2, 20, false],\n\t] as [number, number, boolean][]).forEach(([quantity, total, member]) => {\n",
+				"\t\t}) // ",
+				"\t})\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := defaultSpecSettings()
+			settings.framework = tt.framework
+			if tt.format != nil {
+				settings.format = *tt.format
+			}
+			document := parseAcText("", acs, settings.config.Acs)
+			result := renderAcs(document.Nodes, settings.forAcs(document))
+
+			for _, phrase := range tt.expected {
+				if !strings.Contains(result, phrase) {
+					t.Errorf("renderAcs() does not contain expected phrase %q in:\n%s", phrase, result)
+				}
+			}
+		})
+	}
+}
+
+func TestExampleIdentifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   []string
+		expected []string
+	}{
+		{"Words", []string{"first name", "E-mail address"}, []string{"firstName", "eMailAddress"}},
+		{"Reserved words", []string{"default", "new", "in", "class"}, []string{"default_", "new_", "in_", "class_"}},
+		{"Duplicates", []string{"price", "Price", "price"}, []string{"price", "price2", "price3"}},
+		{"Multi-byte letters", []string{"prix été", "1st"}, []string{"prixÉté", "_1st"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := exampleIdentifiers(tt.header); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("exampleIdentifiers(%q) = %q, want %q", tt.header, result, tt.expected)
+			}
+		})
+	}
+}
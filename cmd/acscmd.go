@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var acsCmd = &cobra.Command{
+	Use:   "acs",
+	Short: "Parse and render acceptance criteria without generating a spec",
+}
+
+var acsParseOutput string
+
+var acsParseCmd = &cobra.Command{
+	Use:   "parse [file]",
+	Short: "Print the AC tree of a file, or stdin, as JSON or YAML",
+	Long: `Parse ACs in any of the supported formats and print the tree: the nodes with
+their levels, titles and source line numbers, and the lines that were ignored.`,
+	Example: `
+	ng-spec acs parse acs.md
+	pbpaste | ng-spec acs parse --output yaml
+	`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := parseAcsCommand(cmd.OutOrStdout(), acsFileArg(args), acsParseOutput); err != nil {
+			printCommandError(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var acsRenderCmd = &cobra.Command{
+	Use:   "render [file]",
+	Short: "Print the spec blocks for an AC tree printed by acs parse",
+	Example: `
+	ng-spec acs parse acs.md | ng-spec acs render
+	`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := renderAcsCommand(cmd.OutOrStdout(), acsFileArg(args)); err != nil {
+			printCommandError(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var acsExportOutput string
+
+var acsExportCmd = &cobra.Command{
+	Use:   "export <spec>",
+	Short: "Print the describe and it tree of a spec as ACs, marking the tests still to implement",
+	Example: `
+	ng-spec acs export src/app/dashboard/dashboard.component.spec.ts
+	ng-spec acs export dashboard.component.spec.ts --output markdown > dashboard.md
+	`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := exportAcsCommand(cmd.OutOrStdout(), args[0], acsExportOutput); err != nil {
+			printCommandError(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	acsParseCmd.Flags().StringVarP(&acsParseOutput, "output", "o", "json", "output format, json or yaml")
+	acsExportCmd.Flags().StringVarP(&acsExportOutput, "output", "o", exportOutline, "output format, outline, markdown or gherkin")
+
+	acsCmd.AddCommand(acsParseCmd, acsRenderCmd, acsExportCmd)
+	rootCmd.AddCommand(acsCmd)
+}
+
+// acsFileArg returns the file argument of an acs command, - for stdin
+func acsFileArg(args []string) string {
+	if len(args) == 0 {
+		return "-"
+	}
+	return args[0]
+}
+
+// parseAcsCommand prints the AC document parsed from a file
+func parseAcsCommand(w io.Writer, fileName, output string) error {
+	if output != "json" && output != "yaml" {
+		return fmt.Errorf("unknown output format %q, use json or yaml", output)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	config, err := findConfig(dir)
+	if err != nil {
+		return err
+	}
+
+	data, err := readAcsFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	document := parseAcText(fileName, string(data), config.Acs)
+	if document.Nodes == nil {
+		document.Nodes = []*acNode{}
+	}
+
+	if output == "yaml" {
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(document); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+// renderAcsCommand prints the spec blocks for an AC document in JSON or YAML,
+// formatted for the project in the working directory
+func renderAcsCommand(w io.Writer, fileName string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	data, err := readAcsFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML, so one decoder reads both
+	var document acDocument
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("invalid AC tree: %w", err)
+	}
+
+	settings, err := acsSettings(filepath.Join(dir, "acs.spec.ts"))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, renderAcs(document.Nodes, settings.forAcs(document)))
+	return err
+}
+
+// acsSettings resolves the settings for rendering ACs into the spec at
+// filePath, from its project, ng-spec.json and formatting options
+func acsSettings(filePath string) (specSettings, error) {
+	dir := filepath.Dir(filePath)
+	ws, err := findWorkspace(dir)
+	if err != nil {
+		return specSettings{}, err
+	}
+
+	settings := ws.projectFor(dir).specSettings()
+	settings.config, err = findConfig(dir)
+	if err != nil {
+		return specSettings{}, err
+	}
+	settings.format, err = resolveFormatOptions(filePath)
+	if err != nil {
+		return specSettings{}, err
+	}
+	return settings, nil
+}
+
+// exportAcsCommand prints the ACs of a spec file
+func exportAcsCommand(w io.Writer, fileName, output string) error {
+	data, err := readAcsFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	text, err := exportSpec(parseSpecBlocks(string(data)), output)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, text)
+	return err
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseAcsCommand(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	fileName := filepath.Join(dir, "acs.txt")
+	if err := os.WriteFile(fileName, []byte("Intro\n1. Dashboard\na. Shows revenue"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		output   string
+		expected []string
+	}{
+		{
+			output: "json",
+			expected: []string{
+				`"title": "Dashboard",`,
+				`"level": 2,`,
+				`"line": 3`,
+				`"reason": "is not a list item"`,
+			},
+		},
+		{
+			output: "yaml",
+			expected: []string{
+				"  - title: Dashboard\n",
+				"        level: 2\n",
+				"        line: 3\n",
+				"    reason: is not a list item\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := parseAcsCommand(&out, fileName, tt.output); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, phrase := range tt.expected {
+				if !strings.Contains(out.String(), phrase) {
+					t.Errorf("parseAcsCommand() does not contain expected phrase %q in:\n%s", phrase, out.String())
+				}
+			}
+		})
+	}
+
+	if err := parseAcsCommand(&bytes.Buffer{}, fileName, "xml"); err == nil {
+		t.Error("parseAcsCommand() should reject unknown output formats")
+	}
+}
+
+func TestRenderAcsCommand(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	source := filepath.Join(dir, "acs.md")
+	if err := os.WriteFile(source, []byte("# Dashboard\n\n- Shows revenue\n  Using the default currency"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed bytes.Buffer
+	if err := parseAcsCommand(&parsed, source, "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	tree := filepath.Join(dir, "acs.json")
+	if err := os.WriteFile(tree, parsed.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := renderAcsCommand(&out, tree); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := renderAcs(parseAcText(source, "# Dashboard\n\n- Shows revenue\n  Using the default currency", acsConfig{}).Nodes, defaultSpecSettings())
+	if out.String() != expected {
+		t.Errorf("renderAcsCommand() = %q, want %q", out.String(), expected)
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Name of the per workspace ng-spec configuration file
+const configFileName = "ng-spec.json"
+
+// ngSpecConfig is the content of ng-spec.json
+type ngSpecConfig struct {
+	Acs      acsConfig      `json:"acs"`
+	Snippets snippetsConfig `json:"snippets"`
+	Titles   titlesConfig   `json:"titles"`
+}
+
+// acsConfig controls how ACs are read
+type acsConfig struct {
+	// Markers lists the enabled numbering and bullet schemes, all by default
+	Markers []string `json:"markers"`
+	// Delimiters lists the enabled delimiters of numbered items, all by default
+	Delimiters []string `json:"delimiters"`
+	// IDs places test case IDs in the test title (default) or in a comment
+	IDs     string        `json:"ids"`
+	CSV     csvConfig     `json:"csv"`
+	Jira    jiraConfig    `json:"jira"`
+	Tracker trackerConfig `json:"tracker"`
+}
+
+// trackerConfig is the issue tracker --ticket fetches ACs from
+type trackerConfig struct {
+	// Type is jira, github or azure
+	Type string `json:"type"`
+	// URL is the site or API root, such as https://example.atlassian.net,
+	// https://api.github.com or https://dev.azure.com/org/project
+	URL string `json:"url"`
+	// Repo is the owner/name of a GitHub repository
+	Repo string `json:"repo"`
+	// User is the Jira Cloud account email, the token is sent as a bearer
+	// token without it
+	User string `json:"user"`
+	// TokenEnv names the environment variable holding the API token
+	TokenEnv string `json:"tokenEnv"`
+	// Field is the Azure DevOps field holding the ACs, Jira fields are set
+	// in the jira section
+	Field string `json:"field"`
+}
+
+// jiraConfig selects the issue field holding the ACs
+type jiraConfig struct {
+	// Field is a field ID such as customfield_10042, description by default
+	Field string `json:"field"`
+	// APIVersion is the REST API version, 3 or 2 for Jira Server and Data
+	// Center. Without it version 3 is tried first, then version 2.
+	APIVersion string `json:"apiVersion"`
+}
+
+// csvConfig maps the columns of test case CSV exports
+type csvConfig struct {
+	// Delimiter separates the fields, detected from the header by default
+	Delimiter string     `json:"delimiter"`
+	Columns   csvColumns `json:"columns"`
+}
+
+// csvColumns names the header of each column, common names are recognised
+// when a column isn't configured
+type csvColumns struct {
+	Section       string `json:"section"`
+	Title         string `json:"title"`
+	ID            string `json:"id"`
+	Preconditions string `json:"preconditions"`
+	Steps         string `json:"steps"`
+	Expected      string `json:"expected"`
+}
+
+// Places for test case IDs
+const (
+	idsInTitle   = "title"
+	idsInComment = "comment"
+)
+
+// Numbering and bullet schemes recognised in AC outlines
+const (
+	schemeDecimal    = "decimal"     // 1.
+	schemeOutline    = "outline"     // 1.1, 1.1.1
+	schemeLowerAlpha = "lower-alpha" // a.
+	schemeUpperAlpha = "upper-alpha" // A.
+	schemeLowerRoman = "lower-roman" // i.
+	schemeUpperRoman = "upper-roman" // I.
+	schemeBullet     = "bullet"      // -, *, •
+	schemeCheckbox   = "checkbox"    // - [ ], - [x]
+)
+
+var allSchemes = []string{
+	schemeDecimal,
+	schemeOutline,
+	schemeLowerAlpha,
+	schemeUpperAlpha,
+	schemeLowerRoman,
+	schemeUpperRoman,
+	schemeBullet,
+	schemeCheckbox,
+}
+
+// Delimiters of numbered items: 1. 1) (1)
+var allDelimiters = []string{".", ")", "()"}
+
+// findConfig walks up from dir to the nearest ng-spec.json. Without one the
+// default configuration is returned.
+func findConfig(dir string) (ngSpecConfig, error) {
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, configFileName))
+		if err == nil {
+			var config ngSpecConfig
+			if err := json.Unmarshal(data, &config); err != nil {
+				return ngSpecConfig{}, fmt.Errorf("invalid %s: %w", configFileName, err)
+			}
+			return config, config.validate()
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ngSpecConfig{}, nil
+		}
+		dir = parent
+	}
+}
+
+func (c ngSpecConfig) validate() error {
+	for _, scheme := range c.Acs.Markers {
+		if !slices.Contains(allSchemes, scheme) {
+			return fmt.Errorf("invalid %s: unknown AC marker %q", configFileName, scheme)
+		}
+	}
+	for _, delimiter := range c.Acs.Delimiters {
+		if !slices.Contains(allDelimiters, delimiter) {
+			return fmt.Errorf("invalid %s: unknown AC delimiter %q", configFileName, delimiter)
+		}
+	}
+	if c.Acs.IDs != "" && c.Acs.IDs != idsInTitle && c.Acs.IDs != idsInComment {
+		return fmt.Errorf("invalid %s: ids must be %q or %q", configFileName, idsInTitle, idsInComment)
+	}
+	if t := c.Acs.Tracker.Type; t != "" && t != trackerJira && t != trackerGitHub && t != trackerAzure {
+		return fmt.Errorf("invalid %s: unknown issue tracker %q", configFileName, t)
+	}
+	if v := c.Acs.Jira.APIVersion; v != "" && !slices.Contains(jiraAPIVersions, v) {
+		return fmt.Errorf("invalid %s: the Jira API version must be %s", configFileName, strings.Join(jiraAPIVersions, " or "))
+	}
+	if utf8.RuneCountInString(c.Acs.CSV.Delimiter) > 1 {
+		return fmt.Errorf("invalid %s: the CSV delimiter must be a single character", configFileName)
+	}
+	for _, rule := range c.Snippets.Rules {
+		if len(rule.Verbs) == 0 || strings.TrimSpace(rule.Snippet) == "" {
+			return fmt.Errorf("invalid %s: snippet rules need verbs and a snippet", configFileName)
+		}
+	}
+	return nil
+}
+
+// allowsScheme reports whether a numbering or bullet scheme is enabled
+func (c acsConfig) allowsScheme(scheme string) bool {
+	return len(c.Markers) == 0 || slices.Contains(c.Markers, scheme)
+}
+
+// allowsDelimiter reports whether a numbered item delimiter is enabled
+func (c acsConfig) allowsDelimiter(delimiter string) bool {
+	return len(c.Delimiters) == 0 || slices.Contains(c.Delimiters, delimiter)
+}
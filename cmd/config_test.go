@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindConfig(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ng-spec-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	config, err := findConfig(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(config, ngSpecConfig{}) {
+		t.Errorf("findConfig() without a config file = %+v, want defaults", config)
+	}
+
+	writeFiles(t, tempDir, map[string]string{
+		configFileName: `{"acs": {"markers": ["decimal", "bullet"], "delimiters": ["."]}}`,
+	})
+
+	config, err = findConfig(filepath.Join(tempDir, "src", "app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !config.Acs.allowsScheme(schemeBullet) || config.Acs.allowsScheme(schemeLowerRoman) {
+		t.Errorf("findConfig() markers = %v, want decimal and bullet only", config.Acs.Markers)
+	}
+	if config.Acs.allowsDelimiter(")") {
+		t.Errorf("findConfig() delimiters = %v, want . only", config.Acs.Delimiters)
+	}
+
+	writeFiles(t, tempDir, map[string]string{
+		configFileName: `{"acs": {"markers": ["hieroglyphs"]}}`,
+	})
+
+	if _, err := findConfig(tempDir); err == nil {
+		t.Error("findConfig() should reject unknown markers")
+	}
+
+	writeFiles(t, tempDir, map[string]string{
+		configFileName: `{"acs": {"ids": "footer"}}`,
+	})
+
+	if _, err := findConfig(tempDir); err == nil {
+		t.Error("findConfig() should reject unknown ID placements")
+	}
+
+	writeFiles(t, tempDir, map[string]string{
+		configFileName: `{"snippets": {"rules": [{"verbs": ["drags"]}]}}`,
+	})
+
+	if _, err := findConfig(tempDir); err == nil {
+		t.Error("findConfig() should reject snippet rules without a snippet")
+	}
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Header names used by TestRail, Zephyr and Xray exports, matched when a
+// column isn't configured
+var csvColumnAliases = map[string][]string{
+	"section":       {"section", "section hierarchy", "folder", "component", "test set", "suite"},
+	"title":         {"title", "name", "summary", "test case", "test summary"},
+	"id":            {"id", "case id", "key", "issue key", "test case id", "test key"},
+	"preconditions": {"preconditions", "precondition", "pre-conditions"},
+	"steps":         {"steps", "step", "test steps", "steps (step)", "action", "step action", "test script (step-by-step) - step"},
+	"expected":      {"expected result", "expected results", "expected", "steps (expected result)", "test script (step-by-step) - expected result"},
+}
+
+var csvSectionSeparatorRegex = regexp.MustCompile(`\s*>\s*`)
+
+// parseCSV builds the AC tree from a test case export. Sections become
+// groups and each case a test carrying its ID, preconditions and steps.
+// Rows without a title or ID continue the previous case, as in exports
+// that list one step per row.
+func parseCSV(text string, config csvConfig) acDocument {
+	var document acDocument
+
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(text, "\ufeff")))
+	reader.Comma = csvDelimiter(text, config.Delimiter)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			document.Diagnostics = append(document.Diagnostics, acDiagnostic{1, "", "is not a valid CSV header: " + err.Error()})
+		}
+		return document
+	}
+
+	columns := csvColumnIndexes(header, config.Columns)
+	if columns["title"] < 0 {
+		document.Diagnostics = append(document.Diagnostics, acDiagnostic{1, strings.Join(header, string(reader.Comma)), "has no title column"})
+		return document
+	}
+
+	sections := make(map[string]*acNode)
+	var current *acNode
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			document.Diagnostics = append(document.Diagnostics, acDiagnostic{line, "", "is not a valid CSV row: " + err.Error()})
+			continue
+		}
+
+		field := func(column string) string {
+			index := columns[column]
+			if index < 0 || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		title, id := field("title"), field("id")
+		if title == "" && id == "" {
+			if current == nil {
+				if text := strings.TrimSpace(strings.Join(record, " ")); text != "" {
+					document.Diagnostics = append(document.Diagnostics, acDiagnostic{line, text, "has no title"})
+				}
+				continue
+			}
+			appendTestSteps(current, field("steps"), field("expected"))
+			continue
+		}
+		if title == "" {
+			document.Diagnostics = append(document.Diagnostics, acDiagnostic{line, id, "has no title"})
+			current = nil
+			continue
+		}
+
+		current = &acNode{Title: title, ID: id, Line: line, Preconditions: nonEmptyLines(field("preconditions"))}
+		appendTestSteps(current, field("steps"), field("expected"))
+
+		parent := csvSection(&document, sections, field("section"), line)
+		if parent == nil {
+			current.Level = 1
+			document.Nodes = append(document.Nodes, current)
+		} else {
+			current.Level = parent.Level + 1
+			parent.Children = append(parent.Children, current)
+		}
+	}
+
+	return document
+}
+
+// csvDelimiter returns the configured delimiter, or the most frequent of
+// comma, semicolon and tab in the header line
+func csvDelimiter(text, configured string) rune {
+	if configured != "" {
+		r, _ := utf8.DecodeRuneInString(configured)
+		return r
+	}
+
+	header, _, _ := strings.Cut(text, "\n")
+	delimiter, count := ',', strings.Count(header, ",")
+	for _, candidate := range []rune{';', '\t'} {
+		if n := strings.Count(header, string(candidate)); n > count {
+			delimiter, count = candidate, n
+		}
+	}
+	return delimiter
+}
+
+// csvColumnIndexes finds the index of each known column, -1 when missing
+func csvColumnIndexes(header []string, configured csvColumns) map[string]int {
+	names := map[string]string{
+		"section":       configured.Section,
+		"title":         configured.Title,
+		"id":            configured.ID,
+		"preconditions": configured.Preconditions,
+		"steps":         configured.Steps,
+		"expected":      configured.Expected,
+	}
+
+	indexes := make(map[string]int)
+	for column, name := range names {
+		candidates := csvColumnAliases[column]
+		if name != "" {
+			candidates = []string{name}
+		}
+
+		indexes[column] = -1
+		for _, candidate := range candidates {
+			if index := csvHeaderIndex(header, candidate); index >= 0 {
+				indexes[column] = index
+				break
+			}
+		}
+	}
+	return indexes
+}
+
+func csvHeaderIndex(header []string, name string) int {
+	for i, cell := range header {
+		if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff")), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// csvSection returns the group for a section path such as "Dashboard >
+// Widgets" or the folder "/Dashboard/Widgets", creating it when needed
+func csvSection(document *acDocument, sections map[string]*acNode, path string, line int) *acNode {
+	var names []string
+	if strings.HasPrefix(path, "/") {
+		names = strings.Split(strings.Trim(path, "/"), "/")
+	} else {
+		names = csvSectionSeparatorRegex.Split(path, -1)
+	}
+
+	var parent *acNode
+	key := ""
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		key += "\x00" + name
+		section, ok := sections[key]
+		if !ok {
+			section = &acNode{Title: name, Line: line, Level: 1}
+			if parent == nil {
+				document.Nodes = append(document.Nodes, section)
+			} else {
+				section.Level = parent.Level + 1
+				parent.Children = append(parent.Children, section)
+			}
+			sections[key] = section
+		}
+		parent = section
+	}
+	return parent
+}
+
+// appendTestSteps adds the steps and expected results of an imported test
+// case, one per line
+func appendTestSteps(node *acNode, steps, expected string) {
+	for _, step := range nonEmptyLines(steps) {
+		node.Steps = append(node.Steps, acStep{Keyword: stepAction, Text: step})
+	}
+	for _, result := range nonEmptyLines(expected) {
+		node.Steps = append(node.Steps, acStep{Keyword: stepExpected, Text: result})
+	}
+}
+
+// nonEmptyLines splits a multi-line value into its non-empty, trimmed lines
+func nonEmptyLines(cell string) []string {
+	var lines []string
+	for _, line := range strings.Split(cell, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
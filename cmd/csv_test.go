@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		config   csvConfig
+		expected string
+	}{
+		{
+			name:     "TestRail sections",
+			text:     "ID,Title,Section Hierarchy,Steps\nC1,Shows revenue,Dashboard > Widgets,Open the dashboard\nC2,Saves layout,Dashboard > Settings,\nC3,Shows orders,Dashboard > Widgets,",
+			expected: "Dashboard(Widgets(Shows revenue Shows orders) Settings(Saves layout))",
+		},
+		{
+			name:     "Zephyr folders with semicolons",
+			text:     "Key;Name;Folder\nDASH-T1;Shows revenue;/Dashboard/Widgets\nDASH-T2;Without folder;",
+			expected: "Dashboard(Widgets(Shows revenue)) Without folder",
+		},
+		{
+			name:     "Configured columns",
+			text:     "Case,Scenario\n1,Shows revenue",
+			config:   csvConfig{Columns: csvColumns{ID: "Case", Title: "Scenario"}},
+			expected: "Shows revenue",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := outlineShape(parseCSV(tt.text, tt.config).Nodes); result != tt.expected {
+				t.Errorf("parseCSV() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseCSVCases(t *testing.T) {
+	text := "Issue Key,Summary,Precondition,Action,Expected Result\n" +
+		"DASH-1,Shows revenue,\"Logged in\nHas orders\",Open the dashboard,The revenue widget is shown\n" +
+		",,,Switch currency,Amounts are converted\n" +
+		"DASH-2,,,,\n"
+	document := parseCSV(text, csvConfig{})
+
+	revenue := document.Nodes[0]
+	if revenue.ID != "DASH-1" || !reflect.DeepEqual(revenue.Preconditions, []string{"Logged in", "Has orders"}) {
+		t.Errorf("Expected DASH-1 with two preconditions, got %q with %q", revenue.ID, revenue.Preconditions)
+	}
+
+	expectedSteps := []acStep{
+		{stepAction, "Open the dashboard"},
+		{stepExpected, "The revenue widget is shown"},
+		{stepAction, "Switch currency"},
+		{stepExpected, "Amounts are converted"},
+	}
+	if !reflect.DeepEqual(revenue.Steps, expectedSteps) {
+		t.Errorf("Expected steps %q, got %q", expectedSteps, revenue.Steps)
+	}
+
+	expected := []acDiagnostic{{5, "DASH-2", "has no title"}}
+	if !reflect.DeepEqual(document.Diagnostics, expected) {
+		t.Errorf("parseCSV() diagnostics = %+v, want %+v", document.Diagnostics, expected)
+	}
+}
+
+func TestRenderCSVCase(t *testing.T) {
+	node := &acNode{
+		Title:         "Shows revenue",
+		ID:            "C1",
+		Preconditions: []string{"Logged in"},
+		Steps:         []acStep{{stepAction, "Open the dashboard"}, {stepExpected, "The revenue widget is shown"}},
+	}
+
+	tests := []struct {
+		name     string
+		ids      string
+		expected []string
+	}{
+		{
+			name: "IDs in titles",
+			expected: []string{
+				"it('[C1] should show revenue', async () => {",
+				"\t\t// Preconditions\n\t\t// Logged in\n\n\t\t// Act\n\t\t// Open the dashboard\n\n\t\t// Assert\n\t\t// The revenue widget is shown\n",
+			},
+		},
+		{
+			name:     "IDs in comments",
+			ids:      idsInComment,
+			expected: []string{"it('should show revenue', async () => {\n\t\t// C1\n\t\tconst {"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := defaultSpecSettings()
+			settings.config.Acs.IDs = tt.ids
+
+			result := renderAcs([]*acNode{node}, settings)
+			for _, phrase := range tt.expected {
+				if !strings.Contains(result, phrase) {
+					t.Errorf("renderAcs() does not contain expected phrase %q in:\n%s", phrase, result)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var docxHeadingStyleRegex = regexp.MustCompile(`(?i)^(?:heading\s?([1-6])|title)$`)
+
+// docxParagraph is a paragraph of a Word document with the properties that
+// decide its place in the AC tree
+type docxParagraph struct {
+	text string
+	// heading is the heading level, 0 for body text
+	heading int
+	// level is the list level of a numbered or bulleted paragraph, -1 for
+	// other paragraphs
+	level int
+}
+
+// parseDocx builds the AC tree from a Word document. Heading paragraphs
+// become groups and numbered or bulleted paragraphs list items nested by
+// their list level, other paragraphs are notes of the item above.
+func parseDocx(data []byte, config acsConfig) acDocument {
+	paragraphs, err := readDocxParagraphs(data)
+	if err != nil {
+		return acDocument{Diagnostics: []acDiagnostic{{1, "", "is not a valid .docx document: " + err.Error()}}}
+	}
+
+	var lines []string
+	for _, paragraph := range paragraphs {
+		text := strings.TrimSpace(paragraph.text)
+		switch {
+		case text == "":
+			continue
+		case paragraph.heading > 0:
+			lines = append(lines, "", strings.Repeat("#", paragraph.heading)+" "+strings.ReplaceAll(text, "\n", " "))
+		case paragraph.level >= 0:
+			lines = append(lines, strings.Repeat("  ", paragraph.level)+"- "+text)
+		default:
+			lines = append(lines, text)
+		}
+	}
+
+	return parseMarkdown(strings.Join(lines, "\n"), config)
+}
+
+// readDocxParagraphs reads the paragraphs of word/document.xml
+func readDocxParagraphs(data []byte) ([]docxParagraph, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := archive.Open("word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var paragraphs []docxParagraph
+	var current *docxParagraph
+	var text strings.Builder
+	inText, numbered := false, false
+
+	decoder := xml.NewDecoder(file)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			switch token.Name.Local {
+			case "p":
+				current = &docxParagraph{level: -1}
+				text.Reset()
+				numbered = false
+			case "pStyle":
+				if current != nil {
+					if matches := docxHeadingStyleRegex.FindStringSubmatch(docxVal(token)); matches != nil {
+						current.heading = 1
+						if matches[1] != "" {
+							current.heading, _ = strconv.Atoi(matches[1])
+						}
+					}
+				}
+			case "outlineLvl":
+				if level, err := strconv.Atoi(docxVal(token)); current != nil && err == nil && level < 6 {
+					current.heading = level + 1
+				}
+			case "numPr":
+				numbered = true
+			case "ilvl":
+				if level, err := strconv.Atoi(docxVal(token)); current != nil && numbered && err == nil {
+					current.level = level
+				}
+			case "numId":
+				// Numbering ID 0 removes the numbering of a list style
+				if current != nil && numbered {
+					if docxVal(token) == "0" {
+						current.level = -1
+						numbered = false
+					} else if current.level < 0 {
+						current.level = 0
+					}
+				}
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteString(" ")
+			case "br", "cr":
+				text.WriteString("\n")
+			}
+
+		case xml.EndElement:
+			switch token.Name.Local {
+			case "t":
+				inText = false
+			case "numPr":
+				numbered = false
+			case "p":
+				if current != nil {
+					current.text = text.String()
+					paragraphs = append(paragraphs, *current)
+					current = nil
+				}
+			}
+
+		case xml.CharData:
+			if inText {
+				text.Write(token)
+			}
+		}
+	}
+
+	return paragraphs, nil
+}
+
+func docxVal(element xml.StartElement) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == "val" {
+			return attr.Value
+		}
+	}
+	return ""
+}
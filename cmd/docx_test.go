@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// docxFile builds a minimal Word document from WordprocessingML paragraphs
+func docxFile(t *testing.T, paragraphs ...string) []byte {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	file, err := archive.Create("word/document.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		strings.Join(paragraphs, "") +
+		`</w:body></w:document>`
+	if _, err := file.Write([]byte(body)); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func docxHeading(style, text string) string {
+	return `<w:p><w:pPr><w:pStyle w:val="` + style + `"/></w:pPr><w:r><w:t>` + text + `</w:t></w:r></w:p>`
+}
+
+func docxListItem(level, text string) string {
+	return `<w:p><w:pPr><w:pStyle w:val="ListParagraph"/><w:numPr><w:ilvl w:val="` + level + `"/><w:numId w:val="3"/></w:numPr></w:pPr>` +
+		`<w:r><w:t xml:space="preserve">` + text + `</w:t></w:r></w:p>`
+}
+
+func docxText(text string) string {
+	return `<w:p><w:r><w:t>` + text + `</w:t></w:r></w:p>`
+}
+
+func TestParseDocx(t *testing.T) {
+	data := docxFile(t,
+		docxHeading("Title", "Dashboard"),
+		docxText("Agreed with product."),
+		docxHeading("Heading2", "Widgets"),
+		docxListItem("0", "Shows the revenue"),
+		docxListItem("1", "In the account currency"),
+		docxText("Rounded to cents"),
+		docxListItem("0", "Shows orders"),
+		`<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="0"/></w:numPr></w:pPr><w:r><w:t>Numbering removed</w:t></w:r></w:p>`,
+	)
+
+	document := parseAcText("spec.docx", string(data), acsConfig{})
+
+	expected := "Dashboard(Widgets(Shows the revenue(In the account currency) Shows orders))"
+	if result := outlineShape(document.Nodes); result != expected {
+		t.Errorf("parseDocx() = %q, want %q", result, expected)
+	}
+
+	widgets := document.Nodes[0].Children[0]
+	if notes := widgets.Children[0].Children[0].Notes; len(notes) != 1 || notes[0] != "Rounded to cents" {
+		t.Errorf("Expected the paragraph below an item as notes, got %q", notes)
+	}
+	if notes := widgets.Children[1].Notes; len(notes) != 1 || notes[0] != "Numbering removed" {
+		t.Errorf("Expected the unnumbered paragraph as notes, got %q", notes)
+	}
+
+	invalid := parseDocx([]byte("not a zip"), acsConfig{})
+	if len(invalid.Diagnostics) != 1 || len(invalid.Nodes) != 0 {
+		t.Errorf("parseDocx() should report invalid documents, got %+v", invalid)
+	}
+}
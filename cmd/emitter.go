@@ -0,0 +1,543 @@
+package cmd
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// The TypeScript code model below is shared by every generator, so that
+// escaping and formatting rules live in one place and the output is always
+// syntactically valid.
+
+// tsNode is a statement, comment or blank line
+type tsNode interface {
+	printNode(p *tsPrinter, level int)
+}
+
+// tsExpr is an expression. The first line of the result is unindented, any
+// following lines carry their own indentation. used is the number of columns
+// already taken on the line the expression starts on.
+type tsExpr interface {
+	printExpr(p *tsPrinter, level, used int) string
+}
+
+type tsPrinter struct {
+	format formatOptions
+	out    strings.Builder
+}
+
+func newTsPrinter(format formatOptions) *tsPrinter {
+	return &tsPrinter{format: format}
+}
+
+func (p *tsPrinter) line(level int, text string) {
+	if text == "" {
+		p.out.WriteString("\n")
+		return
+	}
+	p.out.WriteString(p.format.indent(level) + text + "\n")
+}
+
+// column returns the width taken by the indentation of a level
+func (p *tsPrinter) column(level int) int {
+	return level * p.format.tabWidth
+}
+
+// printNodes prints nodes at the given level and returns the result
+func printNodes(format formatOptions, level int, nodes []tsNode) string {
+	p := newTsPrinter(format)
+	for _, node := range nodes {
+		node.printNode(p, level)
+	}
+	return p.out.String()
+}
+
+// separated inserts a blank line between nodes, skipping nil ones
+func separated(nodes ...tsNode) []tsNode {
+	var result []tsNode
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		if len(result) > 0 {
+			result = append(result, tsBlank{})
+		}
+		result = append(result, node)
+	}
+	return result
+}
+
+// Statements
+
+type tsBlank struct{}
+
+func (tsBlank) printNode(p *tsPrinter, level int) {
+	p.line(level, "")
+}
+
+// tsGroup prints nodes one after the other, such as a comment and the
+// statement it documents
+type tsGroup []tsNode
+
+func (g tsGroup) printNode(p *tsPrinter, level int) {
+	for _, node := range g {
+		node.printNode(p, level)
+	}
+}
+
+// tsComment is a line comment, one line per line of text
+type tsComment string
+
+func (c tsComment) printNode(p *tsPrinter, level int) {
+	for _, line := range strings.Split(string(c), "\n") {
+		p.line(level, strings.TrimRight("// "+line, " "))
+	}
+}
+
+// tsTrailingComment prints a statement with a line comment after it
+type tsTrailingComment struct {
+	node tsNode
+	text string
+}
+
+func (c tsTrailingComment) printNode(p *tsPrinter, level int) {
+	inner := newTsPrinter(p.format)
+	c.node.printNode(inner, level)
+	p.out.WriteString(strings.TrimSuffix(inner.out.String(), "\n") + " // " + c.text + "\n")
+}
+
+// tsDocComment is a JSDoc block, one entry per line
+type tsDocComment []string
+
+func (c tsDocComment) printNode(p *tsPrinter, level int) {
+	p.line(level, "/**")
+	for _, line := range c {
+		line = escapeJsDoc(line)
+		p.line(level, strings.TrimRight(" * "+line, " "))
+	}
+	p.line(level, " */")
+}
+
+// escapeJsDoc keeps text, such as a link with */ in it, from closing the
+// JSDoc comment it's written in
+func escapeJsDoc(text string) string {
+	return strings.ReplaceAll(text, "*/", "*\\/")
+}
+
+// tsExprStatement is an expression followed by the statement terminator.
+// Without semicolons, a statement starting with a bracket, parenthesis or
+// template literal would continue the previous one, so it is preceded by a
+// semicolon the way Prettier prints it.
+type tsExprStatement struct {
+	expr tsExpr
+}
+
+func (s tsExprStatement) printNode(p *tsPrinter, level int) {
+	prefix := ""
+	if !p.format.semi {
+		prefix = ";"
+	}
+	expr := s.expr.printExpr(p, level, p.column(level)+len(prefix))
+	if !strings.HasPrefix(expr, "(") && !strings.HasPrefix(expr, "[") && !strings.HasPrefix(expr, "`") {
+		prefix = ""
+	}
+	p.line(level, prefix+expr+p.format.terminator())
+}
+
+// tsConst declares a constant, the name may be a destructuring pattern
+type tsConst struct {
+	name  string
+	value tsExpr
+}
+
+func (s tsConst) printNode(p *tsPrinter, level int) {
+	prefix := "const " + s.name + " = "
+	p.line(level, prefix+s.value.printExpr(p, level, p.column(level)+len(prefix))+p.format.terminator())
+}
+
+// tsLet declares a variable assigned later, such as in a beforeEach hook
+type tsLet struct {
+	name, typ string
+}
+
+func (s tsLet) printNode(p *tsPrinter, level int) {
+	p.line(level, "let "+s.name+": "+s.typ+p.format.terminator())
+}
+
+type tsReturn struct {
+	value tsExpr
+}
+
+func (s tsReturn) printNode(p *tsPrinter, level int) {
+	p.line(level, "return "+s.value.printExpr(p, level, p.column(level)+len("return "))+p.format.terminator())
+}
+
+// Expressions
+
+// tsRaw is an identifier or an expression that needs no further formatting
+type tsRaw string
+
+func (e tsRaw) printExpr(p *tsPrinter, level, used int) string {
+	return string(e)
+}
+
+// tsString is a string literal, escaped for the quote style in use
+type tsString string
+
+func (e tsString) printExpr(p *tsPrinter, level, used int) string {
+	return p.format.stringLiteral(string(e))
+}
+
+// tsTemplate is a template literal, its parts alternate between text and
+// interpolated expressions
+type tsTemplate []string
+
+func (e tsTemplate) printExpr(p *tsPrinter, level, used int) string {
+	replacer := strings.NewReplacer("\\", "\\\\", "`", "\\`", "${", "\\${")
+
+	var result strings.Builder
+	result.WriteString("`")
+	for i, part := range e {
+		if i%2 == 0 {
+			result.WriteString(replacer.Replace(part))
+		} else {
+			result.WriteString("${" + part + "}")
+		}
+	}
+	result.WriteString("`")
+	return result.String()
+}
+
+// tsCast asserts the type of an expression, in parentheses so that members
+// can be called on it
+type tsCast struct {
+	expr tsExpr
+	typ  string
+}
+
+func (e tsCast) printExpr(p *tsPrinter, level, used int) string {
+	return "(" + e.expr.printExpr(p, level, used+1) + " as " + e.typ + ")"
+}
+
+type tsAwait struct {
+	expr tsExpr
+}
+
+func (e tsAwait) printExpr(p *tsPrinter, level, used int) string {
+	return "await " + e.expr.printExpr(p, level, used+len("await "))
+}
+
+// tsCall is a function call, trailing object and function arguments hug the
+// parentheses the way Prettier prints them
+type tsCall struct {
+	callee string
+	args   []tsExpr
+}
+
+func (e tsCall) printExpr(p *tsPrinter, level, used int) string {
+	return printArgs(p, level, used, e.callee, e.args)
+}
+
+// tsChain calls a member of an expression, or the expression itself when
+// member is empty, as in it.each(rows)('title', fn) or rows.forEach(fn)
+type tsChain struct {
+	target tsExpr
+	member string
+	args   []tsExpr
+}
+
+func (e tsChain) printExpr(p *tsPrinter, level, used int) string {
+	head := e.target.printExpr(p, level, used)
+	if e.member != "" {
+		head += "." + e.member
+	}
+	return printArgs(p, level, used, head, e.args)
+}
+
+func printArgs(p *tsPrinter, level, used int, head string, args []tsExpr) string {
+	var result strings.Builder
+	result.WriteString(head + "(")
+
+	for i, arg := range args {
+		if i > 0 {
+			result.WriteString(", ")
+		}
+		result.WriteString(arg.printExpr(p, level, columnAfter(used, result.String())))
+	}
+
+	result.WriteString(")")
+	return result.String()
+}
+
+// tsArrow is an arrow function, params is the parameter list as written
+// between the parentheses
+type tsArrow struct {
+	async  bool
+	params string
+	body   []tsNode
+}
+
+func (e tsArrow) printExpr(p *tsPrinter, level, used int) string {
+	head := "(" + e.params + ") => {"
+	if e.async {
+		head = "async " + head
+	}
+
+	if len(e.body) == 0 {
+		return head + "}"
+	}
+
+	return head + "\n" + printNodes(p.format, level+1, e.body) + p.format.indent(level) + "}"
+}
+
+// tsArray is an array literal, printed on one line when it fits
+type tsArray []tsExpr
+
+func (e tsArray) printExpr(p *tsPrinter, level, used int) string {
+	var items []string
+	multiline := false
+	for _, item := range e {
+		printed := item.printExpr(p, level+1, p.column(level+1))
+		multiline = multiline || strings.Contains(printed, "\n")
+		items = append(items, printed)
+	}
+
+	inline := "[" + strings.Join(items, ", ") + "]"
+
+	// Without a print width only single item arrays stay on one line
+	fits := len(items) <= 1
+	if p.format.printWidth > 0 {
+		fits = p.format.fits(used, inline+",")
+	}
+
+	if len(items) == 0 || (fits && !multiline) {
+		return inline
+	}
+
+	var result strings.Builder
+	result.WriteString("[\n")
+	for i, item := range items {
+		result.WriteString(p.format.indent(level+1) + item + p.format.itemComma(i, len(items)) + "\n")
+	}
+	result.WriteString(p.format.indent(level) + "]")
+	return result.String()
+}
+
+// tsTuple is an array literal kept on one line whenever it fits, like the
+// rows of a table
+type tsTuple []tsExpr
+
+func (e tsTuple) printExpr(p *tsPrinter, level, used int) string {
+	var items []string
+	for _, item := range e {
+		items = append(items, item.printExpr(p, level+1, p.column(level+1)))
+	}
+
+	inline := "[" + strings.Join(items, ", ") + "]"
+	if p.format.fits(used, inline+",") && !strings.Contains(inline, "\n") {
+		return inline
+	}
+	return tsArray(e).printExpr(p, level, used)
+}
+
+// tsProperty is an object literal property. A property without a value is
+// printed in shorthand form, one without a key is a comment line.
+type tsProperty struct {
+	key     string
+	value   tsExpr
+	comment string
+}
+
+// tsObject is an object literal. Inline objects stay on one line when they
+// fit, others are always expanded like hand-written configuration objects.
+type tsObject struct {
+	properties []tsProperty
+	inline     bool
+}
+
+func (e tsObject) printExpr(p *tsPrinter, level, used int) string {
+	if len(e.properties) == 0 {
+		return "{}"
+	}
+
+	if e.inline {
+		var parts []string
+		for _, property := range e.properties {
+			parts = append(parts, property.printProperty(p, level))
+		}
+		inline := "{ " + strings.Join(parts, ", ") + " }"
+		if p.format.fits(used, inline+";") && !strings.Contains(inline, "\n") {
+			return inline
+		}
+	}
+
+	var entries []tsProperty
+	for _, property := range e.properties {
+		if property.key != "" {
+			entries = append(entries, property)
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString("{\n")
+
+	index := 0
+	for _, property := range e.properties {
+		if property.key == "" {
+			result.WriteString(p.format.indent(level+1) + "// " + property.comment + "\n")
+			continue
+		}
+
+		result.WriteString(p.format.indent(level+1) + property.printProperty(p, level+1) + p.format.itemComma(index, len(entries)))
+		if property.comment != "" {
+			result.WriteString(" // " + property.comment)
+		}
+		result.WriteString("\n")
+		index++
+	}
+
+	result.WriteString(p.format.indent(level) + "}")
+	return result.String()
+}
+
+func (property tsProperty) printProperty(p *tsPrinter, level int) string {
+	if property.value == nil {
+		return property.key
+	}
+
+	prefix := property.key + ": "
+	return prefix + property.value.printExpr(p, level, p.column(level)+len(prefix))
+}
+
+// Files
+
+type tsImport struct {
+	names []string
+	from  string
+}
+
+func (i tsImport) printNode(p *tsPrinter, level int) {
+	from := p.format.stringLiteral(i.from)
+	line := "import { " + strings.Join(i.names, ", ") + " } from " + from + p.format.terminator()
+
+	if len(i.names) == 1 || p.format.fits(p.column(level), line) {
+		p.line(level, line)
+		return
+	}
+
+	p.line(level, "import {")
+	for index, name := range i.names {
+		p.line(level+1, name+p.format.itemComma(index, len(i.names)))
+	}
+	p.line(level, "} from "+from+p.format.terminator())
+}
+
+// tsFile is a module: package imports, relative imports and the body, each
+// group separated by a blank line
+type tsFile struct {
+	imports []tsImport
+	body    []tsNode
+}
+
+func (f tsFile) print(format formatOptions) string {
+	var packages, relative []tsNode
+	for _, imp := range f.imports {
+		if strings.HasPrefix(imp.from, ".") {
+			relative = append(relative, imp)
+		} else {
+			packages = append(packages, imp)
+		}
+	}
+
+	var groups []tsNode
+	for _, group := range [][]tsNode{packages, relative, f.body} {
+		if len(group) == 0 {
+			continue
+		}
+		if len(groups) > 0 {
+			groups = append(groups, tsBlank{})
+		}
+		groups = append(groups, group...)
+	}
+
+	return printNodes(format, 0, groups)
+}
+
+// Test blocks
+
+// tsTestBlock creates a describe, it or hook call such as
+// describe('title', () => { ... }) or beforeEach(async () => { ... })
+func tsTestBlock(callee, title string, async bool, body ...tsNode) tsNode {
+	var args []tsExpr
+	if callee != "beforeEach" && callee != "afterEach" {
+		args = append(args, tsString(title))
+	}
+	args = append(args, tsArrow{async: async, body: body})
+
+	return tsExprStatement{tsCall{callee: callee, args: args}}
+}
+
+func tsDescribe(title string, body ...tsNode) tsNode {
+	return tsTestBlock("describe", title, false, body...)
+}
+
+// String literals
+
+// stringLiteral quotes s the way Prettier does: the preferred quote unless
+// the other one needs fewer escapes, and a template literal for multi-line
+// text
+func (f formatOptions) stringLiteral(s string) string {
+	if strings.ContainsAny(s, "\n\r") {
+		replacer := strings.NewReplacer("\\", "\\\\", "`", "\\`", "${", "\\${", "\r", "")
+		return "`" + replacer.Replace(s) + "`"
+	}
+
+	quote, alternate := "'", `"`
+	if !f.singleQuote {
+		quote, alternate = alternate, quote
+	}
+	if strings.Count(s, quote) > strings.Count(s, alternate) {
+		quote = alternate
+	}
+
+	var result strings.Builder
+	result.WriteString(quote)
+	for _, r := range s {
+		switch {
+		case r == '\\':
+			result.WriteString(`\\`)
+		case string(r) == quote:
+			result.WriteString(`\` + quote)
+		case r == '\t':
+			result.WriteString(`\t`)
+		case r == '\u2028':
+			result.WriteString(`\u2028`)
+		case r == '\u2029':
+			result.WriteString(`\u2029`)
+		case r < ' ':
+			// Other control characters have no readable place in a title
+			result.WriteString(" ")
+		default:
+			result.WriteRune(r)
+		}
+	}
+	result.WriteString(quote)
+
+	return result.String()
+}
+
+// itemComma returns the comma following item i of n in a multi-line list
+func (f formatOptions) itemComma(i, n int) string {
+	if i < n-1 {
+		return ","
+	}
+	return f.listComma()
+}
+
+// columnAfter returns the column reached after printing s from column used
+func columnAfter(used int, s string) int {
+	if i := strings.LastIndex(s, "\n"); i >= 0 {
+		return utf8.RuneCountInString(s[i+1:])
+	}
+	return used + utf8.RuneCountInString(s)
+}
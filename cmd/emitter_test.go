@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringLiteral(t *testing.T) {
+	single := defaultFormatOptions()
+	double := formatOptions{semi: true}
+
+	tests := []struct {
+		name     string
+		format   formatOptions
+		value    string
+		expected string
+	}{
+		{"Plain text", single, "should save", "'should save'"},
+		{"Double quote preference", double, "should save", `"should save"`},
+		{"Apostrophe switches quotes", single, "should show can't save", `"should show can't save"`},
+		{"Fewest escapes", single, `should show "can't" and 'won't'`, `"should show \"can't\" and 'won't'"`},
+		{"Backslash", single, `should accept C:\path`, `'should accept C:\\path'`},
+		{"Tab", single, "should\tsave", `'should\tsave'`},
+		{"Multi-line uses template literal", single, "line one\nline `two` ${x}", "`line one\nline \\`two\\` \\${x}`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.format.stringLiteral(tt.value); result != tt.expected {
+				t.Errorf("stringLiteral(%q) = %s, want %s", tt.value, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestImportPrinting(t *testing.T) {
+	f := formatOptions{tabWidth: 2, semi: true, trailingComma: "es5", printWidth: 40}
+
+	result := printNodes(f, 0, []tsNode{tsImport{[]string{"HttpTestingController", "provideHttpClientTesting"}, "@angular/common/http/testing"}})
+	expected := "import {\n  HttpTestingController,\n  provideHttpClientTesting,\n} from \"@angular/common/http/testing\";\n"
+	if result != expected {
+		t.Errorf("tsImport printed %q, want %q", result, expected)
+	}
+
+	result = printNodes(f, 0, []tsNode{tsImport{[]string{"render"}, "@testing-library/angular"}})
+	expected = "import { render } from \"@testing-library/angular\";\n"
+	if result != expected {
+		t.Errorf("tsImport printed %q, want %q", result, expected)
+	}
+}
+
+func TestTestBlockPrinting(t *testing.T) {
+	f := formatOptions{tabWidth: 2, singleQuote: true, trailingComma: "none", printWidth: 80}
+
+	nodes := []tsNode{
+		tsDescribe("User's profile", separated(
+			tsTestBlock("beforeEach", "", false, tsExprStatement{tsRaw("setup()")}),
+			tsTestBlock("it", "should save", true,
+				tsConst{"{ view }", tsAwait{tsCall{"mount", nil}}},
+				tsComment("TODO: Implement test"),
+			),
+		)...),
+	}
+
+	expected := `describe("User's profile", () => {
+  beforeEach(() => {
+    setup()
+  })
+
+  it('should save', async () => {
+    const { view } = await mount()
+    // TODO: Implement test
+  })
+})
+`
+
+	if result := printNodes(f, 0, nodes); result != expected {
+		t.Errorf("printNodes() = \n%s\nwant\n%s", result, expected)
+	}
+}
+
+func TestStatementsWithoutSemicolons(t *testing.T) {
+	f := formatOptions{tabWidth: 2, singleQuote: true, trailingComma: "none", printWidth: 80}
+
+	tests := []struct {
+		name     string
+		node     tsNode
+		expected string
+	}{
+		{"Call", tsExprStatement{tsCall{"setup", nil}}, "setup()\n"},
+		{"Array", tsExprStatement{tsChain{tsArray{tsString("a")}, "forEach", []tsExpr{tsRaw("run")}}}, ";['a'].forEach(run)\n"},
+		{"Parenthesis", tsExprStatement{tsChain{tsCast{tsArray{tsRaw("1")}, "number[]"}, "forEach", []tsExpr{tsRaw("run")}}}, ";([1] as number[]).forEach(run)\n"},
+		{"Template literal", tsExprStatement{tsTemplate{"a"}}, ";`a`\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := printNodes(f, 0, []tsNode{tt.node}); result != tt.expected {
+				t.Errorf("printNodes() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	f.semi = true
+	if result := printNodes(f, 0, []tsNode{tests[1].node}); result != "['a'].forEach(run);\n" {
+		t.Errorf("printNodes() with semicolons = %q", result)
+	}
+}
+
+func TestObjectAndArrayPrinting(t *testing.T) {
+	f := formatOptions{tabWidth: 2, semi: true, trailingComma: "all", printWidth: 40}
+
+	node := tsConst{"options", tsObject{properties: []tsProperty{
+		{comment: "TODO: Add imports"},
+		{key: "imports", value: tsArray{}},
+		{key: "providers", value: tsArray{tsRaw("provideHttpClient()"), tsRaw("provideMockStore()")}},
+		{key: "name", value: tsString("a"), comment: "TODO: Provide a value"},
+	}}}
+
+	expected := `const options = {
+  // TODO: Add imports
+  imports: [],
+  providers: [
+    provideHttpClient(),
+    provideMockStore(),
+  ],
+  name: 'a', // TODO: Provide a value
+};
+`
+
+	f.singleQuote = true
+	if result := printNodes(f, 0, []tsNode{node}); result != expected {
+		t.Errorf("printNodes() = \n%s\nwant\n%s", result, expected)
+	}
+
+	f.printWidth = 0
+	if result := printNodes(f, 0, []tsNode{tsReturn{tsObject{properties: shorthandProperties("view", "loader"), inline: true}}}); !strings.Contains(result, "return { view, loader };") {
+		t.Errorf("inline object printed %q", result)
+	}
+}
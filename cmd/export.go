@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Formats ACs can be exported to
+const (
+	exportOutline  = "outline"
+	exportMarkdown = "markdown"
+	exportGherkin  = "gherkin"
+)
+
+// exportSpec writes the describe and it tree of a spec as ACs in one of the
+// formats they're read from, so that the spec can be reviewed against the
+// ticket. Tests that are still the generated stub are marked as TODO, and
+// the generated "should create" test isn't an AC.
+func exportSpec(blocks []*specBlock, format string) (string, error) {
+	blocks = exportBlocks(blocks)
+
+	var lines []string
+	switch format {
+	case exportOutline:
+		lines = exportOutlineLines(blocks, 0)
+	case exportMarkdown:
+		lines = exportMarkdownLines(blocks)
+	case exportGherkin:
+		lines = exportGherkinLines(gherkinFeatures(blocks), nil)
+	default:
+		return "", fmt.Errorf("unknown export format %q, use %s, %s or %s", format, exportOutline, exportMarkdown, exportGherkin)
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// exportBlocks returns the blocks without the generated "should create" test
+func exportBlocks(blocks []*specBlock) []*specBlock {
+	var exported []*specBlock
+	for _, block := range blocks {
+		if !block.isDescribe() && block.Title == traceBoilerplateTest {
+			continue
+		}
+		if len(block.Children) > 0 {
+			copied := *block
+			copied.Children = exportBlocks(block.Children)
+			block = &copied
+		}
+		exported = append(exported, block)
+	}
+	return exported
+}
+
+// Values of a table row in an it.each title, as in "(quantity: %s)"
+var exportEachValueRegex = regexp.MustCompile(`([^\s(,:][^(,:]*?):\s*%[sdifjop]`)
+
+// specAcTitle returns the AC a block was generated from, without the
+// "should" the tests are titled with. The values of it.each rows are named
+// after their column, as Jasmine loops are.
+func specAcTitle(block *specBlock) string {
+	title := strings.Join(strings.Fields(block.Title), " ")
+	if block.isDescribe() {
+		return title
+	}
+	if strings.Contains(block.Callee, ".each") {
+		title = exportEachValueRegex.ReplaceAllString(title, "$1: <$1>")
+		title = strings.ReplaceAll(title, "%%", "%")
+	}
+	if len(title) > 7 && strings.EqualFold(title[:7], "should ") {
+		title = title[7:]
+	}
+	return ucFirst(title)
+}
+
+// exportOutlineLines numbers the blocks 1., a., i. by depth, as in the AC
+// format example
+func exportOutlineLines(blocks []*specBlock, depth int) []string {
+	var lines []string
+	for i, block := range blocks {
+		line := strings.Repeat("  ", depth) + outlineNumber(depth, i+1, len(blocks)) + " " + specAcTitle(block)
+		if block.Todo {
+			line += " (TODO)"
+		}
+		lines = append(lines, line)
+		lines = append(lines, exportOutlineLines(block.Children, depth+1)...)
+	}
+	return lines
+}
+
+// outlineNumber returns the marker of the nth of count items at depth. Lists
+// too long for letters are numbered instead.
+func outlineNumber(depth, n, count int) string {
+	switch {
+	case depth%3 == 1 && count <= 26:
+		return string(rune('a'+n-1)) + "."
+	case depth%3 == 2:
+		return romanNumeral(n) + "."
+	}
+	return strconv.Itoa(n) + "."
+}
+
+func romanNumeral(n int) string {
+	numerals := []struct {
+		value  int
+		symbol string
+	}{
+		{1000, "m"}, {900, "cm"}, {500, "d"}, {400, "cd"}, {100, "c"}, {90, "xc"},
+		{50, "l"}, {40, "xl"}, {10, "x"}, {9, "ix"}, {5, "v"}, {4, "iv"}, {1, "i"},
+	}
+
+	var result strings.Builder
+	for _, numeral := range numerals {
+		for ; n >= numeral.value; n -= numeral.value {
+			result.WriteString(numeral.symbol)
+		}
+	}
+	return result.String()
+}
+
+// exportMarkdownLines writes top-level describe blocks as headings and
+// everything below them as a nested list, tests as tasks that are checked
+// once implemented
+func exportMarkdownLines(blocks []*specBlock) []string {
+	var lines []string
+	var list []*specBlock
+
+	flush := func() {
+		if len(list) > 0 {
+			lines = append(lines, exportMarkdownList(list, "")...)
+			lines = append(lines, "")
+			list = nil
+		}
+	}
+
+	for _, block := range blocks {
+		if !block.isDescribe() {
+			list = append(list, block)
+			continue
+		}
+		flush()
+		lines = append(lines, "# "+specAcTitle(block), "")
+		lines = append(lines, exportMarkdownList(block.Children, "")...)
+		lines = append(lines, "")
+	}
+	flush()
+
+	return lines[:max(len(lines)-1, 0)]
+}
+
+func exportMarkdownList(blocks []*specBlock, indent string) []string {
+	var lines []string
+	for _, block := range blocks {
+		switch {
+		case block.isDescribe():
+			lines = append(lines, indent+"- "+specAcTitle(block))
+		case block.Todo:
+			lines = append(lines, indent+"- [ ] "+specAcTitle(block))
+		default:
+			lines = append(lines, indent+"- [x] "+specAcTitle(block))
+		}
+		lines = append(lines, exportMarkdownList(block.Children, indent+"  ")...)
+	}
+	return lines
+}
+
+// exportGherkinLines writes top-level describe blocks as features, nested
+// ones as rules and tests as scenarios. Gherkin has no nested rules, so
+// deeper describe titles are joined into the rule title, and scenarios come
+// before the rules of their describe so that they don't end up in them.
+func exportGherkinLines(blocks []*specBlock, parents []string) []string {
+	indent := "  "
+	if len(parents) > 1 {
+		indent = "    "
+	}
+
+	var lines []string
+	for _, block := range blocks {
+		title := specAcTitle(block)
+		switch {
+		case !block.isDescribe():
+			if block.Todo {
+				lines = append(lines, indent+"# TODO")
+			}
+			header := examplesHeader(title)
+			if len(header) == 0 {
+				lines = append(lines, indent+"Scenario: "+title, "")
+				continue
+			}
+			lines = append(lines, indent+"Scenario Outline: "+title, "", indent+"  Examples:")
+			lines = append(lines, exportExamplesLines(header, block.Examples, indent+"    ")...)
+			lines = append(lines, "")
+		case len(parents) == 0:
+			lines = append(lines, strings.TrimSpace("Feature: "+title), "")
+			lines = append(lines, exportGherkinLines(testsFirst(block.Children), []string{title})...)
+		default:
+			path := slices.Concat(parents[1:], []string{title})
+			lines = append(lines, "  Rule: "+strings.Join(path, " > "), "")
+			lines = append(lines, exportGherkinLines(testsFirst(block.Children), slices.Concat(parents, []string{title}))...)
+		}
+	}
+	if len(parents) == 0 && len(lines) > 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// gherkinFeatures moves tests outside any describe into the first describe,
+// the component's, as scenarios need a feature. Without a describe they get
+// a feature of their own.
+func gherkinFeatures(blocks []*specBlock) []*specBlock {
+	var loose []*specBlock
+	feature := -1
+	for i, block := range blocks {
+		switch {
+		case !block.isDescribe():
+			loose = append(loose, block)
+		case feature < 0:
+			feature = i
+		}
+	}
+	if len(loose) == 0 {
+		return blocks
+	}
+	if feature < 0 {
+		return []*specBlock{{Callee: "describe", Children: loose}}
+	}
+
+	component := *blocks[feature]
+	component.Children = slices.Concat(loose, component.Children)
+	var features []*specBlock
+	for i, block := range blocks {
+		switch {
+		case i == feature:
+			features = append(features, &component)
+		case block.isDescribe():
+			features = append(features, block)
+		}
+	}
+	return features
+}
+
+// examplesHeader returns the <placeholders> of a scenario title, the columns
+// of its examples
+func examplesHeader(title string) []string {
+	var header []string
+	for _, match := range examplePlaceholderRegex.FindAllStringSubmatch(title, -1) {
+		if !slices.Contains(header, match[1]) {
+			header = append(header, match[1])
+		}
+	}
+	return header
+}
+
+// exportExamplesLines writes the Examples table of a scenario outline. Object
+// rows fill the columns by name and array rows by position, rows that don't
+// fill every column are left out.
+func exportExamplesLines(header []string, rows []specRow, indent string) []string {
+	cellReplacer := strings.NewReplacer(`\`, `\\`, "|", `\|`, "\n", `\n`)
+	tableLine := func(cells []string) string {
+		for i, cell := range cells {
+			cells[i] = cellReplacer.Replace(cell)
+		}
+		return indent + "| " + strings.Join(cells, " | ") + " |"
+	}
+
+	lines := []string{tableLine(slices.Clone(header))}
+	for _, row := range rows {
+		var cells []string
+		for i, column := range header {
+			index := slices.IndexFunc(row, func(value specValue) bool { return value.key == column })
+			if index < 0 && i < len(row) && row[i].key == "" {
+				index = i
+			}
+			if index < 0 {
+				break
+			}
+			cells = append(cells, row[index].value)
+		}
+		if len(cells) == len(header) {
+			lines = append(lines, tableLine(cells))
+		}
+	}
+	return lines
+}
+
+func testsFirst(blocks []*specBlock) []*specBlock {
+	var tests, describes []*specBlock
+	for _, block := range blocks {
+		if block.isDescribe() {
+			describes = append(describes, block)
+		} else {
+			tests = append(tests, block)
+		}
+	}
+	return append(tests, describes...)
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+const exportSource = `describe('DashboardComponent', () => {
+	it('should create', async () => {
+		expect(true).toBe(true);
+	});
+
+	it('should render', async () => {
+		expect(true).toBe(true);
+	});
+
+	describe('Widgets', () => {
+		it('should show the revenue', async () => {
+			// TODO: Implement test
+		});
+
+		describe('Layout', () => {
+			it('should save the layout', async () => {});
+		});
+	});
+
+	it('should load the user', async () => {
+		// TODO: Implement test
+	});
+
+	it.each<[number, number]>([
+		[1, 10],
+	])('should apply 100%% off (quantity: %s, total: %s)', async (quantity, total) => {});
+});
+`
+
+func TestExportSpec(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: exportOutline,
+			expected: `1. DashboardComponent
+  a. Render
+  b. Widgets
+    i. Show the revenue (TODO)
+    ii. Layout
+      1. Save the layout
+  c. Load the user (TODO)
+  d. Apply 100% off (quantity: <quantity>, total: <total>)
+`,
+		},
+		{
+			format: exportMarkdown,
+			expected: `# DashboardComponent
+
+- [x] Render
+- Widgets
+  - [ ] Show the revenue
+  - Layout
+    - [x] Save the layout
+- [ ] Load the user
+- [x] Apply 100% off (quantity: <quantity>, total: <total>)
+`,
+		},
+		{
+			format: exportGherkin,
+			expected: `Feature: DashboardComponent
+
+  Scenario: Render
+
+  # TODO
+  Scenario: Load the user
+
+  Scenario Outline: Apply 100% off (quantity: <quantity>, total: <total>)
+
+    Examples:
+      | quantity | total |
+      | 1 | 10 |
+
+  Rule: Widgets
+
+    # TODO
+    Scenario: Show the revenue
+
+  Rule: Widgets > Layout
+
+    Scenario: Save the layout
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			result, err := exportSpec(parseSpecBlocks(exportSource), tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("exportSpec() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExportSpecRoundTrip(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{exportOutline, "DashboardComponent(Render Widgets(Show the revenue (TODO) Layout(Save the layout)) Load the user (TODO) Apply 100% off (quantity: <quantity>, total: <total>))"},
+		{exportMarkdown, "DashboardComponent(Render Widgets(Show the revenue Layout(Save the layout)) Load the user Apply 100% off (quantity: <quantity>, total: <total>))"},
+		{exportGherkin, "DashboardComponent(Render Load the user Apply 100% off (quantity: <quantity>, total: <total>) Widgets(Show the revenue) Widgets > Layout(Save the layout))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			result, _ := exportSpec(parseSpecBlocks(exportSource), tt.format)
+			document := parseAcText("", result, acsConfig{})
+
+			if len(document.Diagnostics) > 0 {
+				t.Errorf("Exported ACs have diagnostics %+v", document.Diagnostics)
+			}
+			if shape := outlineShape(document.Nodes); shape != tt.expected {
+				t.Errorf("Exported ACs parse as %q, want %q", shape, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExportGherkinOutlines(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name: "Object rows",
+			source: "describe('LoginComponent', () => {\n" +
+				"\tit.each([\n\t\t{ attempts: '3', state: 'locked' },\n\t\t{ attempts: 5, state: \"a|b\" },\n\t])('should show $state after $attempts attempts', async ({ attempts, state }) => {});\n" +
+				"});\n",
+			expected: "Feature: LoginComponent\n\n" +
+				"  Scenario Outline: Show <state> after <attempts> attempts\n\n" +
+				"    Examples:\n      | state | attempts |\n      | locked | 3 |\n      | a\\|b | 5 |\n",
+		},
+		{
+			name: "Jasmine loop",
+			source: "[{ attempts: '3' }].forEach(({ attempts }) => {\n" +
+				"\tit(`should lock after ${attempts} attempts`, async () => {});\n" +
+				"});\n",
+			expected: "Feature:\n\n  Scenario Outline: Lock after <attempts> attempts\n\n    Examples:\n      | attempts |\n",
+		},
+		{
+			name: "Tests outside the component",
+			source: "describe('LoginComponent', () => {\n\tit('should render', async () => {});\n});\n\n" +
+				"it('should load', async () => {});\n",
+			expected: "Feature: LoginComponent\n\n  Scenario: Load\n\n  Scenario: Render\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := exportSpec(parseSpecBlocks(tt.source), exportGherkin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("exportSpec() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExportGherkinRoundTripExamples(t *testing.T) {
+	result, _ := exportSpec(parseSpecBlocks(exportSource), exportGherkin)
+	document := parseAcText("", result, acsConfig{})
+
+	outline := document.Nodes[0].Children[2]
+	if outline.Examples == nil || !reflect.DeepEqual(outline.Examples.Header, []string{"quantity", "total"}) ||
+		!reflect.DeepEqual(outline.Examples.Rows, [][]string{{"1", "10"}}) {
+		t.Errorf("Exported outline %q parses with examples %+v", outline.Title, outline.Examples)
+	}
+}
@@ -0,0 +1,412 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// formatOptions controls the layout of the generated TypeScript
+type formatOptions struct {
+	useTabs       bool
+	tabWidth      int
+	singleQuote   bool
+	semi          bool
+	trailingComma string // "all", "es5" or "none"
+	printWidth    int    // 0 disables wrapping
+}
+
+// defaultFormatOptions matches the output of ng-spec when the project has no
+// formatting configuration
+func defaultFormatOptions() formatOptions {
+	return formatOptions{
+		useTabs:       true,
+		tabWidth:      4,
+		singleQuote:   true,
+		semi:          true,
+		trailingComma: "all",
+	}
+}
+
+// prettierDefaultOptions are the options Prettier uses for anything its
+// configuration doesn't set
+func prettierDefaultOptions() formatOptions {
+	return formatOptions{
+		tabWidth:      2,
+		semi:          true,
+		trailingComma: "all",
+		printWidth:    80,
+	}
+}
+
+// prettierConfig mirrors the supported subset of a Prettier configuration
+type prettierConfig struct {
+	UseTabs       *bool   `json:"useTabs" yaml:"useTabs"`
+	TabWidth      *int    `json:"tabWidth" yaml:"tabWidth"`
+	SingleQuote   *bool   `json:"singleQuote" yaml:"singleQuote"`
+	Semi          *bool   `json:"semi" yaml:"semi"`
+	TrailingComma *string `json:"trailingComma" yaml:"trailingComma"`
+	PrintWidth    *int    `json:"printWidth" yaml:"printWidth"`
+	Overrides     []struct {
+		Files   stringList     `json:"files" yaml:"files"`
+		Options prettierConfig `json:"options" yaml:"options"`
+	} `json:"overrides" yaml:"overrides"`
+}
+
+// stringList accepts either a single string or a list of strings
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = stringList{node.Value}
+		return nil
+	}
+
+	var values []string
+	if err := node.Decode(&values); err != nil {
+		return err
+	}
+	*l = values
+	return nil
+}
+
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*l = stringList{value}
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*l = values
+	return nil
+}
+
+func (c prettierConfig) applyTo(options formatOptions) formatOptions {
+	if c.UseTabs != nil {
+		options.useTabs = *c.UseTabs
+	}
+	if c.TabWidth != nil {
+		options.tabWidth = *c.TabWidth
+	}
+	if c.SingleQuote != nil {
+		options.singleQuote = *c.SingleQuote
+	}
+	if c.Semi != nil {
+		options.semi = *c.Semi
+	}
+	if c.TrailingComma != nil {
+		options.trailingComma = *c.TrailingComma
+	}
+	if c.PrintWidth != nil {
+		options.printWidth = *c.PrintWidth
+	}
+	return options
+}
+
+// Prettier configuration files in the order Prettier looks them up
+var prettierConfigFiles = []string{
+	".prettierrc",
+	".prettierrc.json",
+	".prettierrc.yaml",
+	".prettierrc.yml",
+}
+
+// findPrettierConfig walks up from dir and returns the first Prettier
+// configuration found along with the directory it was found in
+func findPrettierConfig(dir string) (*prettierConfig, string, error) {
+	for {
+		if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+			var manifest struct {
+				Prettier *prettierConfig `json:"prettier"`
+			}
+			// A shared config referenced by name can't be resolved, skip it
+			if json.Unmarshal(data, &manifest) == nil && manifest.Prettier != nil {
+				return manifest.Prettier, dir, nil
+			}
+		}
+
+		for _, name := range prettierConfigFiles {
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				continue
+			}
+
+			// JSON is valid YAML, so one decoder handles every supported format
+			var config prettierConfig
+			if err := yaml.Unmarshal(data, &config); err != nil {
+				return nil, "", err
+			}
+			return &config, dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, "", nil
+		}
+		dir = parent
+	}
+}
+
+type editorconfigSection struct {
+	pattern    *regexp.Regexp
+	properties map[string]string
+}
+
+type editorconfigFile struct {
+	dir      string
+	root     bool
+	sections []editorconfigSection
+}
+
+func parseEditorconfig(dir string, data []byte) editorconfigFile {
+	file := editorconfigFile{dir: dir}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			file.sections = append(file.sections, editorconfigSection{
+				pattern:    editorconfigGlob(line[1 : len(line)-1]),
+				properties: map[string]string{},
+			})
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.ToLower(strings.TrimSpace(value))
+
+		if len(file.sections) == 0 {
+			if key == "root" {
+				file.root = value == "true"
+			}
+			continue
+		}
+		file.sections[len(file.sections)-1].properties[key] = value
+	}
+
+	return file
+}
+
+// editorconfigGlob converts an EditorConfig section glob to a regular
+// expression matched against slash separated paths relative to the file
+func editorconfigGlob(glob string) *regexp.Regexp {
+	var pattern strings.Builder
+
+	if strings.Contains(glob, "/") {
+		glob = strings.TrimPrefix(glob, "/")
+		pattern.WriteString("^")
+	} else {
+		pattern.WriteString("^(?:.*/)?")
+	}
+
+	braceDepth := 0
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+
+		switch {
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			pattern.WriteString(".*")
+			i++
+		case c == '*':
+			pattern.WriteString("[^/]*")
+		case c == '?':
+			pattern.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				pattern.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			pattern.WriteString("[" + class + "]")
+			i += end
+		case c == '{':
+			end := strings.IndexByte(glob[i:], '}')
+			if end > 0 && regexp.MustCompile(`^-?\d+\.\.-?\d+$`).MatchString(glob[i+1:i+end]) {
+				pattern.WriteString(`[+-]?\d+`)
+				i += end
+				continue
+			}
+			braceDepth++
+			pattern.WriteString("(?:")
+		case c == '}' && braceDepth > 0:
+			braceDepth--
+			pattern.WriteString(")")
+		case c == ',' && braceDepth > 0:
+			pattern.WriteString("|")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			pattern.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	pattern.WriteString("$")
+
+	regex, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return regexp.MustCompile(`^$`)
+	}
+	return regex
+}
+
+// editorconfigProperties resolves the EditorConfig properties that apply to
+// filePath, with files closer to it taking precedence
+func editorconfigProperties(filePath string) map[string]string {
+	var files []editorconfigFile
+
+	dir := filepath.Dir(filePath)
+	for {
+		if data, err := os.ReadFile(filepath.Join(dir, ".editorconfig")); err == nil {
+			file := parseEditorconfig(dir, data)
+			files = append(files, file)
+			if file.root {
+				break
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	properties := map[string]string{}
+	for i := len(files) - 1; i >= 0; i-- {
+		rel, err := filepath.Rel(files[i].dir, filePath)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		for _, section := range files[i].sections {
+			if section.pattern.MatchString(rel) {
+				for key, value := range section.properties {
+					properties[key] = value
+				}
+			}
+		}
+	}
+
+	return properties
+}
+
+// resolveFormatOptions combines EditorConfig and Prettier settings for the
+// spec file the same way Prettier does: Prettier options override
+// EditorConfig, which overrides the defaults
+func resolveFormatOptions(filePath string) (formatOptions, error) {
+	prettier, prettierDir, err := findPrettierConfig(filepath.Dir(filePath))
+	if err != nil {
+		return formatOptions{}, err
+	}
+
+	options := defaultFormatOptions()
+	if prettier != nil {
+		options = prettierDefaultOptions()
+	}
+
+	properties := editorconfigProperties(filePath)
+
+	switch properties["indent_style"] {
+	case "tab":
+		options.useTabs = true
+	case "space":
+		options.useTabs = false
+	}
+
+	if size, err := strconv.Atoi(properties["indent_size"]); err == nil {
+		options.tabWidth = size
+	} else if size, err := strconv.Atoi(properties["tab_width"]); err == nil {
+		options.tabWidth = size
+	}
+
+	if width, err := strconv.Atoi(properties["max_line_length"]); err == nil {
+		options.printWidth = width
+	}
+
+	// Prettier ignores quote_type, so it only applies without a Prettier config
+	if prettier == nil {
+		switch properties["quote_type"] {
+		case "single":
+			options.singleQuote = true
+		case "double":
+			options.singleQuote = false
+		}
+	}
+
+	if prettier != nil {
+		options = prettier.applyTo(options)
+
+		rel, err := filepath.Rel(prettierDir, filePath)
+		if err == nil {
+			for _, override := range prettier.Overrides {
+				for _, files := range override.Files {
+					if editorconfigGlob(files).MatchString(filepath.ToSlash(rel)) {
+						options = override.Options.applyTo(options)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return options, nil
+}
+
+// indent returns the indentation for the given nesting level
+func (f formatOptions) indent(level int) string {
+	if f.useTabs {
+		return strings.Repeat("\t", level)
+	}
+	return strings.Repeat(" ", level*f.tabWidth)
+}
+
+// terminator returns the statement terminator
+func (f formatOptions) terminator() string {
+	if f.semi {
+		return ";"
+	}
+	return ""
+}
+
+// listComma returns the trailing comma for the last item of a multi-line
+// array, object or import list
+func (f formatOptions) listComma() string {
+	if f.trailingComma == "none" {
+		return ""
+	}
+	return ","
+}
+
+// fits reports whether text starting at the given column fits the print width
+func (f formatOptions) fits(column int, text string) bool {
+	if f.printWidth <= 0 {
+		return true
+	}
+	return column+utf8.RuneCountInString(text) <= f.printWidth
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEditorconfigGlob(t *testing.T) {
+	tests := []struct {
+		glob     string
+		path     string
+		expected bool
+	}{
+		{"*", "src/app/user.component.spec.ts", true},
+		{"*.ts", "src/app/user.component.spec.ts", true},
+		{"*.{js,ts}", "user.spec.ts", true},
+		{"*.{js,ts}", "user.spec.scss", false},
+		{"src/**.ts", "src/app/user.ts", true},
+		{"src/*.ts", "src/app/user.ts", false},
+		{"/lib/*.ts", "src/lib/user.ts", false},
+		{"*.[jt]s", "user.ts", true},
+		{"file{1..3}.ts", "file2.ts", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.path, func(t *testing.T) {
+			if result := editorconfigGlob(tt.glob).MatchString(tt.path); result != tt.expected {
+				t.Errorf("editorconfigGlob(%q).MatchString(%q) = %v, want %v", tt.glob, tt.path, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveFormatOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected formatOptions
+	}{
+		{
+			name:     "No configuration",
+			files:    map[string]string{},
+			expected: defaultFormatOptions(),
+		},
+		{
+			name: "EditorConfig only",
+			files: map[string]string{
+				".editorconfig": "root = true\n\n[*]\nindent_style = space\nindent_size = 2\n\n[*.md]\nindent_size = 4\n\n[*.ts]\nquote_type = double\nmax_line_length = 100\n",
+			},
+			expected: formatOptions{tabWidth: 2, semi: true, trailingComma: "all", printWidth: 100},
+		},
+		{
+			name: "Prettier JSON overrides EditorConfig",
+			files: map[string]string{
+				".editorconfig": "[*]\nindent_style = tab\nindent_size = 8\n",
+				".prettierrc":   `{"singleQuote": true, "semi": false, "useTabs": false}`,
+			},
+			expected: formatOptions{tabWidth: 8, singleQuote: true, trailingComma: "all", printWidth: 80},
+		},
+		{
+			name: "Prettier YAML with overrides",
+			files: map[string]string{
+				".prettierrc.yaml": "trailingComma: es5\noverrides:\n  - files: '*.spec.ts'\n    options:\n      printWidth: 120\n",
+			},
+			expected: formatOptions{tabWidth: 2, semi: true, trailingComma: "es5", printWidth: 120},
+		},
+		{
+			name: "Prettier in package.json",
+			files: map[string]string{
+				"package.json": `{"name": "app", "prettier": {"tabWidth": 4, "trailingComma": "none"}}`,
+			},
+			expected: formatOptions{tabWidth: 4, semi: true, trailingComma: "none", printWidth: 80},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir, err := os.MkdirTemp("", "ng-spec-format")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(tempDir)
+
+			writeFiles(t, tempDir, tt.files)
+
+			result, err := resolveFormatOptions(filepath.Join(tempDir, "src", "user.component.spec.ts"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("resolveFormatOptions() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderAcsWithFormatOptions(t *testing.T) {
+	settings := defaultSpecSettings()
+	settings.format = formatOptions{tabWidth: 2, semi: false, trailingComma: "none", printWidth: 80}
+
+	document := parseAcText("", "1. Create user\na. Enter valid data", settings.config.Acs)
+	result := renderAcs(document.Nodes, settings.forAcs(document))
+
+	expectedLines := []string{
+		"  describe(\"Create user\", () => {",
+		"    it(\"should enter valid data\", async () => {",
+		"      const { view, httpTestingController, loader } = await mount()",
+		"    }) // " + acHashComment(&acNode{Title: "Enter valid data"}),
+		"  })",
+	}
+
+	for _, line := range expectedLines {
+		if !strings.Contains(result, line+"\n") {
+			t.Errorf("renderAcs() does not contain expected line %q in:\n%s", line, result)
+		}
+	}
+
+	template := createTemplate("user", settings)
+	content := integrateAcsWithTemplate(template, "", result)
+	if !strings.HasSuffix(content, "  })\n})\n") {
+		t.Errorf("integrateAcsWithTemplate() should close the component block without a semicolon, got:\n%s", content)
+	}
+}
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"path/filepath"
@@ -16,7 +19,21 @@ import (
 
 type userConfirmationInput interface {
 	getConfirmation(prompt string) (bool, error)
-	addACs() (string, string, error)
+	addACs(acsLink, acsText string) (string, string, error)
+	reviewDiagnostics(diagnostics []acDiagnostic) (bool, error)
+}
+
+// generateOptions holds the flags of the generate command
+type generateOptions struct {
+	// acsFile is read instead of showing the ACs form, "-" reads stdin
+	acsFile string
+	// ticket is fetched from the configured issue tracker
+	ticket  string
+	acsLink string
+	// strict fails the run when AC lines were ignored
+	strict bool
+	// merge adds the tests for new ACs to an existing spec without asking
+	merge bool
 }
 
 type userInput struct{}
@@ -27,7 +44,7 @@ func (ui userInput) getConfirmation(prompt string) (bool, error) {
 	_, err := fmt.Scanln(&response)
 
 	if err != nil {
-		if err.Error() == "unexpected newline" {
+		if err.Error() == "unexpected newline" || errors.Is(err, io.EOF) {
 			return false, nil
 		}
 
@@ -37,9 +54,9 @@ func (ui userInput) getConfirmation(prompt string) (bool, error) {
 	return response == "y" || response == "Y", nil
 }
 
-func (ui userInput) addACs() (string, string, error) {
-	acsLinkInput := huh.NewInput().Key("acsLink").Placeholder("ACs Link")
-	acsDescriptionInput := huh.NewText().Key("acsDescription").Placeholder("Add ACs here")
+func (ui userInput) addACs(acsLink, acsText string) (string, string, error) {
+	acsLinkInput := huh.NewInput().Key("acsLink").Placeholder("ACs Link").Value(&acsLink)
+	acsDescriptionInput := huh.NewText().Key("acsDescription").Placeholder("Add ACs here").Value(&acsText)
 	acsDescriptionInput.WithHeight(10)
 	acsDescriptionInput.CharLimit(math.MaxInt32)
 	acsDescriptionInput.ShowLineNumbers(true)
@@ -88,17 +105,67 @@ func (ui userInput) addACs() (string, string, error) {
 		return "", "", err
 	}
 
-	return form.GetString("acsLink"), form.GetString("acsDescription"), nil
+	return acsLink, acsText, nil
+}
+
+// reviewDiagnostics lists the ignored AC lines and asks whether to go back
+// to the form
+func (ui userInput) reviewDiagnostics(diagnostics []acDiagnostic) (bool, error) {
+	var lines []string
+	for _, diagnostic := range diagnostics {
+		lines = append(lines, diagnostic.String())
+	}
+
+	goBack := true
+	err := huh.NewConfirm().
+		Title(fmt.Sprintf("%d AC line(s) will be ignored", len(diagnostics))).
+		Description(strings.Join(lines, "\n")).
+		Affirmative("Go back and fix").
+		Negative("Continue").
+		Value(&goBack).
+		Run()
+
+	return goBack, err
 }
 
-func generateComponentTest(path string) {
+func generateComponentTest(path string, options generateOptions) error {
 	currentWorkingDirectory, err := os.Getwd()
 	if err != nil {
-		printError(err)
-		return
+		return err
+	}
+
+	ws, err := findWorkspace(currentWorkingDirectory)
+	if err != nil {
+		return err
+	}
+
+	project := ws.projectFor(currentWorkingDirectory)
+
+	versions, err := findPackageVersions(currentWorkingDirectory, ws.rootDir())
+	if err != nil {
+		return err
+	}
+
+	var schematic componentSchematic
+	if project != nil {
+		schematic = project.schematic
+	}
+	settings := versions.applyTo(project.specSettings(), schematic)
+
+	settings.config, err = findConfig(currentWorkingDirectory)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range versions.missingPackages(settings.config.Snippets) {
+		printWarning(fmt.Sprintf("%s is not installed, the generated spec depends on it", name))
+	}
+
+	if settings.skipTests {
+		printWarning(fmt.Sprintf("Project %s is configured with skipTests, generating the spec anyway", project.name))
 	}
 
-	componentPath := transformBasePath(path)
+	componentPath := settings.stripPrefix(transformBasePath(path))
 
 	if strings.HasPrefix(path, "/") {
 		baseName := filepath.Base(path)
@@ -112,57 +179,153 @@ func generateComponentTest(path string) {
 		componentPath = filepath.Base(currentWorkingDirectory)
 	}
 
-	filePath, err := createFilePath(path, componentPath, currentWorkingDirectory)
+	filePath, err := createFilePath(path, settings.specFileName(componentPath), currentWorkingDirectory)
 	if err != nil {
-		printError(err)
-		return
+		return err
+	}
+
+	if !filepath.IsAbs(path) {
+		componentDir, err := project.locateComponent(settings.componentFileName(componentPath), currentWorkingDirectory)
+		if err != nil {
+			return err
+		}
+
+		if componentDir != "" {
+			filePath = filepath.Join(componentDir, settings.specFileName(componentPath))
+		}
 	}
 
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		printError(err)
-		return
+		return err
+	}
+
+	settings.format, err = resolveFormatOptions(filePath)
+	if err != nil {
+		return err
 	}
 
 	input := userInput{}
 
-	template := createTemplate(componentPath)
+	template := createTemplate(componentPath, settings)
 
-	useAcs, err := input.getConfirmation("\033[36m Generate the boilerplate based on ACs? (y/N): \033[0m")
-	if err != nil {
-		printError(err)
-		return
+	useAcs := options.acsFile != "" || options.ticket != ""
+	if !useAcs {
+		useAcs, err = input.getConfirmation("\033[36m Generate the boilerplate based on ACs? (y/N): \033[0m")
+		if err != nil {
+			return err
+		}
 	}
 
 	if useAcs {
-		acsLink, acsText, err := input.addACs()
+		acsLink, document, err := readAcs(options, settings, input)
 		if err != nil {
-			if err.Error() == "user aborted" {
-				return
+			if errors.Is(err, huh.ErrUserAborted) {
+				return nil
 			}
 
-			printError(err)
-			return
+			return err
 		}
 
-		if strings.TrimSpace(acsText) != "" {
-			acsBlocks := parseAcs(acsText)
-			template = integrateAcsWithTemplate(template, acsLink, acsBlocks)
+		if options.strict && len(document.Diagnostics) > 0 {
+			return fmt.Errorf("%d AC line(s) ignored in strict mode", len(document.Diagnostics))
+		}
+
+		if _, err := os.Stat(filePath); err == nil && len(document.Nodes) > 0 {
+			merge := options.merge
+			if !merge {
+				prompt := fmt.Sprintf("\033[33m %s already exists. Merge the new ACs into it? (y/N): \033[0m", filePath)
+				merge, err = input.getConfirmation(prompt)
+				if err != nil {
+					return err
+				}
+			}
+			if merge {
+				return mergeTestFile(filePath, document.Nodes, acsLink, settings.forAcs(document))
+			}
+		}
+
+		if len(document.Nodes) > 0 {
+			acsBlocks := renderAcs(document.Nodes, settings.forAcs(document))
+			template = addSpecImports(integrateAcsWithTemplate(template, acsLink, acsBlocks), settings)
 		}
 	}
 
 	err = writeTestFile(filePath, template, input)
 	if err != nil {
 		if err.Error() == "operation cancelled" {
-			return
-		} else {
-			printError(err)
+			return nil
 		}
 
-		return
+		return err
 	}
 
 	fmt.Println("\033[32m Test file generated successfully at", filePath, "\033[0m")
+	return nil
+}
+
+// readAcs reads the ACs from the file or ticket given in the options, or from
+// the form. Ignored lines are printed for files and tickets, in the form they
+// can be fixed before continuing. The form is also shown when a ticket can't
+// be fetched.
+func readAcs(options generateOptions, settings specSettings, input userConfirmationInput) (string, acDocument, error) {
+	if options.acsFile != "" {
+		data, err := readAcsFile(options.acsFile)
+		if err != nil {
+			return "", acDocument{}, err
+		}
+
+		document := parseAcText(options.acsFile, string(data), settings.config.Acs)
+		for _, diagnostic := range document.Diagnostics {
+			printWarning("AC " + diagnostic.String())
+		}
+
+		return cmp.Or(options.acsLink, document.Link), document, nil
+	}
+
+	if options.ticket != "" {
+		document, err := fetchTicket(options.ticket, settings.config.Acs)
+		if err == nil {
+			for _, diagnostic := range document.Diagnostics {
+				printWarning("AC " + diagnostic.String())
+			}
+			return cmp.Or(options.acsLink, document.Link, options.ticket), document, nil
+		}
+
+		printWarning(fmt.Sprintf("Couldn't fetch the ACs of %s: %v", options.ticket, err))
+		options.acsLink = cmp.Or(options.acsLink, options.ticket)
+	}
+
+	acsLink, acsText := options.acsLink, ""
+	for {
+		var err error
+		acsLink, acsText, err = input.addACs(acsLink, acsText)
+		if err != nil {
+			return "", acDocument{}, err
+		}
+
+		document := parseAcText("", acsText, settings.config.Acs)
+		acsLink = cmp.Or(acsLink, document.Link)
+		if len(document.Diagnostics) == 0 {
+			return acsLink, document, nil
+		}
+
+		goBack, err := input.reviewDiagnostics(document.Diagnostics)
+		if err != nil {
+			return "", acDocument{}, err
+		}
+		if !goBack {
+			return acsLink, document, nil
+		}
+	}
+}
+
+// readAcsFile reads an AC file, or stdin when the name is -
+func readAcsFile(name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(name)
 }
 
 func transformBasePath(path string) string {
@@ -190,9 +353,7 @@ func transformBasePath(path string) string {
 	return basePath
 }
 
-func createFilePath(basePath, componentName, currentWorkingDirectory string) (string, error) {
-	fileName := componentName + ".component.spec.ts"
-
+func createFilePath(basePath, fileName, currentWorkingDirectory string) (string, error) {
 	if basePath == "" {
 		return filepath.Join(currentWorkingDirectory, fileName), nil
 	}
@@ -231,53 +392,103 @@ func printError(err error) {
 	fmt.Printf("\033[31m Error generating test file: %v \033[0m\n", err)
 }
 
-func createTemplate(componentPath string) string {
+// printCommandError reports the failure of a subcommand, which doesn't
+// generate a test file
+func printCommandError(err error) {
+	fmt.Printf("\033[31m Error: %v \033[0m\n", err)
+}
+
+func printWarning(message string) {
+	fmt.Printf("\033[33m Warning: %s \033[0m\n", message)
+}
+
+func createTemplate(componentPath string, settings specSettings) string {
 	importName := strings.ToLower(componentPath)
 	caser := cases.Title(language.English)
-	componentName := caser.String(componentPath)
+	componentName := caser.String(componentPath) + caser.String(settings.componentType)
 	componentName = strings.ReplaceAll(componentName, "-", "")
 
-	template := fmt.Sprintf(`
-import { TestbedHarnessEnvironment } from '@angular/cdk/testing/testbed';
-import { provideHttpClient } from '@angular/common/http';
-import { HttpTestingController, provideHttpClientTesting } from '@angular/common/http/testing';
-import { TestBed } from '@angular/core/testing';
-import { provideMockStore } from '@ngrx/store/testing';
-import { render } from '@testing-library/angular';
-
-import { %sComponent } from './%s.component';
-
-/**
-* ACs from:
-*  - TODO: Link ACs tickets here
-*/
-describe('%sComponent', () => {
-	const mount = async () => {
-		const view = await render(%sComponent, {
-			providers: [
-				provideHttpClient(),
-				provideHttpClientTesting(),
-				provideMockStore(),
-			],
-		});
-
-		const httpTestingController = TestBed.inject(HttpTestingController);
-		const loader = TestbedHarnessEnvironment.loader(view.fixture);
-
-		return { view, httpTestingController, loader };
-	};
-
-	it('should create', async () => {
-		const { view } = await mount();
-		expect(view.fixture.componentInstance).toBeTruthy();
-	});
-});
-`,
-		componentName,
-		importName,
-		componentName,
-		componentName,
+	importPath := importName
+	if settings.componentType != "" {
+		importPath += "." + settings.componentType
+	}
+
+	file := tsFile{}
+	file.imports = append(file.imports, tsImport{[]string{"TestbedHarnessEnvironment"}, "@angular/cdk/testing/testbed"})
+	if settings.httpTestingModule {
+		file.imports = append(file.imports, tsImport{[]string{"HttpClientTestingModule", "HttpTestingController"}, "@angular/common/http/testing"})
+	} else {
+		file.imports = append(file.imports,
+			tsImport{[]string{"provideHttpClient"}, "@angular/common/http"},
+			tsImport{[]string{"HttpTestingController", "provideHttpClientTesting"}, "@angular/common/http/testing"},
+		)
+	}
+	file.imports = append(file.imports, tsImport{[]string{"TestBed"}, "@angular/core/testing"})
+	if settings.mockStore {
+		file.imports = append(file.imports, tsImport{[]string{"provideMockStore"}, "@ngrx/store/testing"})
+	}
+	file.imports = append(file.imports, tsImport{[]string{"render"}, "@testing-library/angular"})
+	if settings.framework == frameworkVitest {
+		file.imports = append(file.imports, tsImport{[]string{"describe", "expect", "it"}, "vitest"})
+	}
+	file.imports = append(file.imports, tsImport{[]string{componentName}, "./" + importPath})
+
+	var renderOptions []tsProperty
+
+	var imports tsArray
+	if settings.httpTestingModule {
+		imports = append(imports, tsRaw("HttpClientTestingModule"))
+	}
+	if !settings.standalone {
+		renderOptions = append(renderOptions, tsProperty{comment: "TODO: Import the NgModule dependencies of " + componentName})
+	}
+	if len(imports) > 0 || !settings.standalone {
+		renderOptions = append(renderOptions, tsProperty{key: "imports", value: imports})
+	}
+
+	var providers tsArray
+	if !settings.httpTestingModule {
+		providers = append(providers, tsRaw("provideHttpClient()"), tsRaw("provideHttpClientTesting()"))
+	}
+	if settings.mockStore {
+		providers = append(providers, tsRaw("provideMockStore()"))
+	}
+	if len(providers) > 0 {
+		renderOptions = append(renderOptions, tsProperty{key: "providers", value: providers})
+	}
+
+	// The inputs are left for the test author, they aren't read from the component
+	renderOptions = append(renderOptions, tsProperty{key: settings.inputsOption, value: tsObject{}, comment: "TODO: Set the component's inputs"})
+
+	mount := tsConst{"mount", tsArrow{async: true, body: []tsNode{
+		tsConst{"view", tsAwait{tsCall{"render", []tsExpr{tsRaw(componentName), tsObject{properties: renderOptions}}}}},
+		tsBlank{},
+		tsConst{"httpTestingController", tsCall{"TestBed.inject", []tsExpr{tsRaw("HttpTestingController")}}},
+		tsConst{"loader", tsCall{"TestbedHarnessEnvironment.loader", []tsExpr{tsRaw("view.fixture")}}},
+		tsBlank{},
+		tsReturn{tsObject{properties: shorthandProperties(mountResults...), inline: true}},
+	}}}
+
+	shouldCreate := tsTestBlock("it", "should create", true,
+		tsConst{"{ view }", tsAwait{tsCall{"mount", nil}}},
+		tsExprStatement{tsRaw("expect(view.fixture.componentInstance).toBeTruthy()")},
 	)
 
-	return strings.TrimPrefix(template, "\n")
+	file.body = []tsNode{
+		tsDocComment{"ACs from:", " - " + acsLinkPlaceholder},
+		tsDescribe(componentName, separated(mount, shouldCreate)...),
+	}
+
+	return file.print(settings.format)
+}
+
+// Values returned by the generated mount function
+var mountResults = []string{"view", "httpTestingController", "loader"}
+
+func shorthandProperties(names ...string) []tsProperty {
+	var properties []tsProperty
+	for _, name := range names {
+		properties = append(properties, tsProperty{key: name})
+	}
+	return properties
 }
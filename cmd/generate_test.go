@@ -12,16 +12,21 @@ type mockUserInput struct {
 	confirmationResponse bool
 	acsLink              string
 	acsText              string
+	goBack               bool
 }
 
 func (m mockUserInput) getConfirmation(prompt string) (bool, error) {
 	return m.confirmationResponse, nil
 }
 
-func (m mockUserInput) addACs() (string, string, error) {
+func (m mockUserInput) addACs(acsLink, acsText string) (string, string, error) {
 	return m.acsLink, m.acsText, nil
 }
 
+func (m mockUserInput) reviewDiagnostics(diagnostics []acDiagnostic) (bool, error) {
+	return m.goBack, nil
+}
+
 func TestTransformBasePath(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -95,7 +100,7 @@ func TestCreateFilePath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := createFilePath(tt.basePath, tt.componentName, tt.currentWorkingDir)
+			result, err := createFilePath(tt.basePath, tt.componentName+".component.spec.ts", tt.currentWorkingDir)
 
 			if err != nil {
 				t.Errorf("createFilePath(%q, %q, %q) unexpected error: %v",
@@ -145,7 +150,7 @@ func TestCreateTemplate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := createTemplate(tt.componentName)
+			result := createTemplate(tt.componentName, defaultSpecSettings())
 
 			for _, phrase := range tt.expectedPhrases {
 				if !strings.Contains(result, phrase) {
@@ -320,10 +325,11 @@ func TestGenerateComponentTestWithACs(t *testing.T) {
 			filePath := filepath.Join(tempDir, tc.expectedFile)
 
 			// Generate the template based on inputs
-			template := createTemplate(tc.componentName)
+			template := createTemplate(tc.componentName, defaultSpecSettings())
 
 			if tc.useACs && tc.acsText != "" {
-				acsBlocks := parseAcs(tc.acsText)
+				document := parseAcText("", tc.acsText, acsConfig{})
+				acsBlocks := renderAcs(document.Nodes, defaultSpecSettings().forAcs(document))
 				template = integrateAcsWithTemplate(template, tc.acsLink, acsBlocks)
 			} else if tc.useACs && tc.acsText == "" && tc.acsLink != "" {
 				// If AC text is empty but link is provided, still update the link
@@ -369,3 +375,41 @@ func TestGenerateComponentTestWithACs(t *testing.T) {
 		})
 	}
 }
+
+func TestReadAcs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ng-spec-acs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	acsFile := filepath.Join(tempDir, "acs.txt")
+	writeFiles(t, tempDir, map[string]string{"acs.txt": "See ticket\n1. Login"})
+
+	tests := []struct {
+		name     string
+		options  generateOptions
+		input    mockUserInput
+		link     string
+		ignored  int
+		criteria int
+	}{
+		{"Form", generateOptions{}, mockUserInput{acsLink: "JIRA-1", acsText: "1. Login\n2. Logout"}, "JIRA-1", 0, 2},
+		{"Form continues with ignored lines", generateOptions{}, mockUserInput{acsText: "Intro\n1. Login"}, "", 1, 1},
+		{"File", generateOptions{acsFile: acsFile, acsLink: "JIRA-2"}, mockUserInput{}, "JIRA-2", 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, document, err := readAcs(tt.options, defaultSpecSettings(), tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if link != tt.link || len(document.Diagnostics) != tt.ignored || len(document.Nodes) != tt.criteria {
+				t.Errorf("readAcs() = %q, %d ignored, %d criteria, want %q, %d, %d",
+					link, len(document.Diagnostics), len(document.Nodes), tt.link, tt.ignored, tt.criteria)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	gherkinLanguageRegex = regexp.MustCompile(`^#\s*language\s*:\s*(\S+)`)
+	gherkinKeywordRegex  = regexp.MustCompile(`^(Feature|Rule|Background|Scenario Outline|Scenario Template|Scenario|Example|Examples|Scenarios):\s*(.*)$`)
+	gherkinStepRegex     = regexp.MustCompile(`^(Given|When|Then|And|But|\*)\s+(.+)$`)
+)
+
+// isGherkin reports whether ACs are written in Gherkin, judging by their
+// first keyword line
+func isGherkin(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "@") {
+			continue
+		}
+		return gherkinKeywordRegex.MatchString(trimmed)
+	}
+	return false
+}
+
+// parseGherkin builds the AC tree from a feature file. Features and rules
+// become groups, backgrounds and scenarios keep their steps, and scenario
+// outlines their examples. Free text below a keyword line is kept as notes.
+func parseGherkin(text string) acDocument {
+	var document acDocument
+	var feature, rule, current *acNode
+	inExamples, expectHeader := false, false
+	docString := ""
+
+	add := func(node *acNode) {
+		parent := rule
+		if parent == nil {
+			parent = feature
+		}
+		if parent == nil {
+			node.Level = 1
+			document.Nodes = append(document.Nodes, node)
+			return
+		}
+		node.Level = parent.Level + 1
+		parent.Children = append(parent.Children, node)
+	}
+
+	lastStep := func() *acStep {
+		if current == nil || len(current.Steps) == 0 {
+			return nil
+		}
+		return &current.Steps[len(current.Steps)-1]
+	}
+
+	report := func(index int, text, reason string) {
+		document.Diagnostics = append(document.Diagnostics, acDiagnostic{index + 1, text, reason})
+	}
+
+	for index, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if docString != "" {
+			if trimmed == docString {
+				docString = ""
+			} else {
+				lastStep().Text += "\n" + trimmed
+			}
+			continue
+		}
+
+		if matches := gherkinLanguageRegex.FindStringSubmatch(trimmed); matches != nil && feature == nil {
+			document.Language = matches[1]
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "@") {
+			continue
+		}
+
+		if matches := gherkinKeywordRegex.FindStringSubmatch(trimmed); matches != nil {
+			keyword, title := matches[1], strings.TrimSpace(matches[2])
+
+			if keyword == "Examples" || keyword == "Scenarios" {
+				if current == nil || current.Kind != acKindScenario {
+					report(index, trimmed, "has examples outside a scenario outline")
+					continue
+				}
+				if current.Examples == nil {
+					current.Examples = &acTable{}
+				}
+				inExamples, expectHeader = true, true
+				continue
+			}
+
+			node := &acNode{Title: title, Line: index + 1}
+			switch keyword {
+			case "Feature":
+				node.Kind = acKindFeature
+				feature, rule = nil, nil
+				add(node)
+				feature = node
+			case "Rule":
+				node.Kind = acKindRule
+				rule = nil
+				add(node)
+				rule = node
+			case "Background":
+				node.Kind = acKindBackground
+				add(node)
+			default:
+				node.Kind = acKindScenario
+				add(node)
+			}
+
+			current = node
+			inExamples = false
+			continue
+		}
+
+		if matches := gherkinStepRegex.FindStringSubmatch(trimmed); matches != nil {
+			if current == nil || (current.Kind != acKindScenario && current.Kind != acKindBackground) || inExamples {
+				report(index, trimmed, "is a step outside a scenario")
+				continue
+			}
+			current.Steps = append(current.Steps, acStep{Keyword: matches[1], Text: strings.TrimSpace(matches[2])})
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, `"""`) || strings.HasPrefix(trimmed, "```") {
+			if lastStep() == nil || inExamples {
+				report(index, trimmed, "is a doc string outside a step")
+				continue
+			}
+			docString = trimmed[:3]
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "|") {
+			switch {
+			case inExamples && expectHeader:
+				if current.Examples.Header == nil {
+					current.Examples.Header = gherkinTableCells(trimmed)
+				}
+				expectHeader = false
+			case inExamples:
+				current.Examples.Rows = append(current.Examples.Rows, gherkinTableCells(trimmed))
+			case lastStep() != nil:
+				// Step data tables are kept with the step
+				lastStep().Text += "\n" + trimmed
+			default:
+				report(index, trimmed, "is a table outside a step or examples")
+			}
+			continue
+		}
+
+		if current != nil && len(current.Steps) == 0 && !inExamples {
+			current.Notes = append(current.Notes, trimmed)
+			continue
+		}
+
+		report(index, trimmed, "is not a Gherkin step")
+	}
+
+	return document
+}
+
+// gherkinTableCells splits a table row such as "| a | b \| c |"
+func gherkinTableCells(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	row = strings.ReplaceAll(row, `\|`, "\x00")
+
+	var cells []string
+	for _, cell := range strings.Split(row, "|") {
+		cells = append(cells, strings.ReplaceAll(strings.TrimSpace(cell), "\x00", "|"))
+	}
+	return cells
+}
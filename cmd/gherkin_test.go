@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const loginFeature = `@smoke
+Feature: Login
+  Users sign in to reach their dashboard.
+
+  Background:
+    Given the login page is open
+
+  Scenario: Valid credentials
+    Given a registered user
+    When they submit their credentials
+      | email | password |
+      | a@b.c | secret   |
+    Then the dashboard is shown
+
+  Rule: Lockout
+    Scenario Outline: Locked after <attempts> failed attempts
+      When they fail <attempts> times
+      Then the account is <state>
+
+      Examples:
+        | attempts | account state |
+        | 3        | locked        |
+        | 2        | unlocked      |
+`
+
+func TestIsGherkin(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected bool
+	}{
+		{"Feature", loginFeature, true},
+		{"Scenario only", "# comment\nScenario: Login\n  Given a user", true},
+		{"Outline", "1. Login\n  a. Valid credentials", false},
+		{"Keyword in an item", "1. Feature: Login", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := isGherkin(tt.text); result != tt.expected {
+				t.Errorf("isGherkin() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseGherkin(t *testing.T) {
+	document := parseGherkin(loginFeature + "Then stray step\n")
+
+	if shape := outlineShape(document.Nodes); shape != "Login( Valid credentials Lockout(Locked after <attempts> failed attempts))" {
+		t.Fatalf("parseGherkin() = %q", shape)
+	}
+
+	feature := document.Nodes[0]
+	if !reflect.DeepEqual(feature.Notes, []string{"Users sign in to reach their dashboard."}) {
+		t.Errorf("Expected the feature description as notes, got %q", feature.Notes)
+	}
+
+	scenario := feature.Children[1]
+	expectedSteps := []acStep{
+		{"Given", "a registered user"},
+		{"When", "they submit their credentials\n| email | password |\n| a@b.c | secret   |"},
+		{"Then", "the dashboard is shown"},
+	}
+	if !reflect.DeepEqual(scenario.Steps, expectedSteps) {
+		t.Errorf("Expected steps %q, got %q", expectedSteps, scenario.Steps)
+	}
+
+	outline := feature.Children[2].Children[0]
+	expectedExamples := &acTable{Header: []string{"attempts", "account state"}, Rows: [][]string{{"3", "locked"}, {"2", "unlocked"}}}
+	if !reflect.DeepEqual(outline.Examples, expectedExamples) || outline.Level != 3 {
+		t.Errorf("Expected examples %v at level 3, got %v at level %d", expectedExamples, outline.Examples, outline.Level)
+	}
+
+	if len(document.Diagnostics) != 1 || document.Diagnostics[0].Reason != "is a step outside a scenario" {
+		t.Errorf("Expected the step after the examples to be reported, got %+v", document.Diagnostics)
+	}
+}
+
+func TestRenderGherkin(t *testing.T) {
+	tests := []struct {
+		name      string
+		framework testFramework
+		noSemi    bool
+		expected  []string
+	}{
+		{
+			name:      "Jest",
+			framework: frameworkJest,
+			expected: []string{
+				"\tdescribe('Login', () => {\n\t\tbeforeEach(async () => {\n\t\t\t// Arrange\n\t\t\t// Given the login page is open\n\t\t\t// TODO: Implement setup\n",
+				"\t\t\t// Act\n\t\t\t// When they submit their credentials\n\t\t\t// | email | password |\n",
+				"\t\t\t// Assert\n\t\t\t// Then the dashboard is shown\n\t\t\t// TODO: Implement test\n",
+				"\t\t\tit.each([\n\t\t\t\t{ attempts: '3', accountState: 'locked' },\n",
+				"])('should locked after $attempts failed attempts', async ({ attempts, accountState }) => {\n",
+			},
+		},
+		{
+			name:      "Jasmine",
+			framework: frameworkKarma,
+			expected: []string{
+				"].forEach(({ attempts, accountState }) => {\n",
+				"it(`should locked after ${attempts} failed attempts`, async () => {\n",
+			},
+		},
+		{
+			name:      "Jasmine without semicolons",
+			framework: frameworkKarma,
+			noSemi:    true,
+			expected: []string{
+				"\t\t\t;[\n\t\t\t\t{ attempts: '3', accountState: 'locked' },\n",
+				"\t\t\t].forEach(({ attempts, accountState }) => {\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := defaultSpecSettings()
+			settings.framework = tt.framework
+			settings.format.printWidth = 80
+			settings.format.semi = !tt.noSemi
+
+			document := parseAcText("", loginFeature, settings.config.Acs)
+			result := renderAcs(document.Nodes, settings.forAcs(document))
+			for _, phrase := range tt.expected {
+				if !strings.Contains(result, phrase) {
+					t.Errorf("renderAcs() does not contain expected phrase %q in:\n%s", phrase, result)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	htmlDocumentRegex   = regexp.MustCompile(`(?i)<(?:html|body|ol|ul|h[1-6])[\s>]`)
+	htmlWhitespaceRegex = regexp.MustCompile(`[ \t\r\n\f]+`)
+)
+
+// Elements whose content isn't part of the document text
+var htmlSkippedElements = map[atom.Atom]bool{
+	atom.Head:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Template: true,
+	atom.Noscript: true,
+}
+
+// Elements that start a new line
+var htmlBlockElements = map[atom.Atom]bool{
+	atom.Address:    true,
+	atom.Article:    true,
+	atom.Aside:      true,
+	atom.Blockquote: true,
+	atom.Body:       true,
+	atom.Dd:         true,
+	atom.Details:    true,
+	atom.Div:        true,
+	atom.Dl:         true,
+	atom.Dt:         true,
+	atom.Figure:     true,
+	atom.Footer:     true,
+	atom.Header:     true,
+	atom.Html:       true,
+	atom.Main:       true,
+	atom.Nav:        true,
+	atom.P:          true,
+	atom.Pre:        true,
+	atom.Section:    true,
+	atom.Summary:    true,
+	atom.Table:      true,
+	atom.Tbody:      true,
+	atom.Thead:      true,
+	atom.Tfoot:      true,
+}
+
+var htmlHeadingLevels = map[atom.Atom]int{
+	atom.H1: 1,
+	atom.H2: 2,
+	atom.H3: 3,
+	atom.H4: 4,
+	atom.H5: 5,
+	atom.H6: 6,
+}
+
+// isHTML reports whether pasted ACs are HTML, such as a Confluence page
+func isHTML(text string) bool {
+	return strings.HasPrefix(strings.TrimSpace(text), "<") && htmlDocumentRegex.MatchString(text)
+}
+
+// htmlToMarkdown converts the headings, lists and paragraphs of an HTML
+// document to Markdown, lists marked as parseAcOutline describes
+func htmlToMarkdown(source string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(source))
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(htmlBlocks(doc, ""), "\n"), nil
+}
+
+// htmlBlocks renders the children of a node, each line prefixed with indent.
+// Blocks are followed by a blank line so that text after a list isn't read
+// as a note of its last item.
+func htmlBlocks(node *html.Node, indent string) []string {
+	var lines []string
+	var text strings.Builder
+
+	flush := func() {
+		for _, line := range strings.Split(text.String(), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, indent+line)
+			}
+		}
+		text.Reset()
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.TextNode {
+			text.WriteString(htmlWhitespaceRegex.ReplaceAllString(child.Data, " "))
+			continue
+		}
+		if child.Type != html.ElementNode || htmlSkippedElements[child.DataAtom] {
+			continue
+		}
+
+		switch {
+		case child.DataAtom == atom.Br:
+			text.WriteString("\n")
+		case htmlHeadingLevels[child.DataAtom] > 0:
+			flush()
+			title := strings.TrimSpace(strings.ReplaceAll(htmlInline(child), "\n", " "))
+			lines = append(lines, indent+strings.Repeat("#", htmlHeadingLevels[child.DataAtom])+" "+title, "")
+		case child.DataAtom == atom.Ul || child.DataAtom == atom.Ol:
+			flush()
+			for item := child.FirstChild; item != nil; item = item.NextSibling {
+				if item.Type == html.ElementNode && item.DataAtom == atom.Li {
+					lines = append(lines, htmlListItem(child, item, indent)...)
+				}
+			}
+			lines = append(lines, "")
+		case child.DataAtom == atom.Tr:
+			flush()
+			var cells []string
+			for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.Type == html.ElementNode {
+					cells = append(cells, strings.TrimSpace(strings.ReplaceAll(htmlInline(cell), "\n", " ")))
+				}
+			}
+			lines = append(lines, indent+"| "+strings.Join(cells, " | ")+" |")
+		case htmlBlockElements[child.DataAtom]:
+			flush()
+			lines = append(lines, htmlBlocks(child, indent)...)
+			lines = append(lines, "")
+		default:
+			text.WriteString(htmlInline(child))
+		}
+	}
+	flush()
+
+	return lines
+}
+
+// htmlListItem renders a list item, its first line on the marker line and
+// anything else indented under it. Confluence task lists become checkboxes.
+func htmlListItem(list, item *html.Node, indent string) []string {
+	marker := "- "
+	if htmlHasClass(list, "inline-task-list") || htmlAttr(item, "data-inline-task-id") != "" {
+		marker = "- [ ] "
+		if htmlHasClass(item, "checked") {
+			marker = "- [x] "
+		}
+	}
+
+	nested := indent + strings.Repeat(" ", len(marker))
+	lines := htmlBlocks(item, nested)
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	lines[0] = indent + marker + strings.TrimPrefix(lines[0], nested)
+	return lines
+}
+
+// htmlInline returns the text of an inline element, line breaks included
+func htmlInline(node *html.Node) string {
+	var result strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		switch {
+		case child.Type == html.TextNode:
+			result.WriteString(htmlWhitespaceRegex.ReplaceAllString(child.Data, " "))
+		case child.Type != html.ElementNode || htmlSkippedElements[child.DataAtom]:
+		case child.DataAtom == atom.Br:
+			result.WriteString("\n")
+		default:
+			result.WriteString(htmlInline(child))
+		}
+	}
+	return result.String()
+}
+
+func htmlAttr(node *html.Node, name string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func htmlHasClass(node *html.Node, class string) bool {
+	for _, name := range strings.Fields(htmlAttr(node, "class")) {
+		if name == class {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHTML builds the AC tree from an HTML document such as a Confluence
+// export
+func parseHTML(text string, config acsConfig) acDocument {
+	markdown, err := htmlToMarkdown(text)
+	if err != nil {
+		return acDocument{Diagnostics: []acDiagnostic{{1, "", "is not a valid HTML document: " + err.Error()}}}
+	}
+	return parseMarkdown(markdown, config)
+}
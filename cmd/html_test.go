@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+const confluencePage = `<!DOCTYPE html>
+<html>
+<head><title>Dashboard</title><style>li { color: red }</style></head>
+<body>
+<h1 id="Dashboard">Dashboard</h1>
+<p>Agreed with
+  product on <a href="https://example.com">Monday</a>.</p>
+<h2>Widgets</h2>
+<ol>
+  <li><p>Shows the <strong>revenue</strong></p>
+    <ul>
+      <li>In the account currency<br/>Rounded to cents</li>
+      <li>With the previous period</li>
+    </ul>
+  </li>
+  <li>Shows orders</li>
+</ol>
+<p>Out of scope: exports</p>
+<h2>Settings</h2>
+<ul class="inline-task-list">
+  <li data-inline-task-id="1" class="checked">Saves layout</li>
+  <li data-inline-task-id="2">Resets layout</li>
+</ul>
+</body>
+</html>`
+
+func TestParseHTML(t *testing.T) {
+	if !isHTML(confluencePage) || isHTML("1. <b>Bold</b> criterion") {
+		t.Fatal("isHTML() should only detect HTML documents")
+	}
+
+	document := parseHTML(confluencePage, acsConfig{})
+
+	expected := "Dashboard(Widgets(Shows the revenue(In the account currency With the previous period) Shows orders) Settings(Saves layout Resets layout))"
+	if result := outlineShape(document.Nodes); result != expected {
+		t.Errorf("parseHTML() = %q, want %q", result, expected)
+	}
+
+	dashboard := document.Nodes[0]
+	if !reflect.DeepEqual(dashboard.Notes, []string{"Agreed with product on Monday."}) {
+		t.Errorf("Expected the paragraph as notes, got %q", dashboard.Notes)
+	}
+
+	currency := dashboard.Children[0].Children[0].Children[0]
+	if !reflect.DeepEqual(currency.Notes, []string{"Rounded to cents"}) {
+		t.Errorf("Expected the line after the break as notes, got %q", currency.Notes)
+	}
+
+	settings := dashboard.Children[1]
+	if settings.Children[0].Marker != "- [x]" || settings.Children[1].Marker != "- [ ]" {
+		t.Errorf("Expected task list checkboxes, got %q and %q", settings.Children[0].Marker, settings.Children[1].Marker)
+	}
+
+	if len(document.Diagnostics) != 1 || document.Diagnostics[0].Text != "Out of scope: exports" {
+		t.Errorf("Expected the paragraph after the list to be reported, got %+v", document.Diagnostics)
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// specImport is an identifier generated code may use, imported when the
+// spec uses it as the usage pattern says
+type specImport struct {
+	name, from string
+	isDefault  bool
+	usage      string
+}
+
+// Vitest has no globals by default, so the hooks of precondition describes
+// and Gherkin backgrounds are imported like describe and it
+var vitestImports = []specImport{
+	{"beforeEach", "vitest", false, `\bbeforeEach\(`},
+}
+
+var (
+	specImportRegex      = regexp.MustCompile(`(?ms)^import\s+([^;'"]*?)\s*from\s*(['"])([^'"]+)['"];?[ \t]*$`)
+	specImportNamesRegex = regexp.MustCompile(`\{([^}]*)\}`)
+)
+
+// addSpecImports imports the identifiers the generated tests use in a spec,
+// adding them to an import from the same package or after the package
+// imports
+func addSpecImports(source string, settings specSettings) string {
+	format := settings.format
+	imports := snippetImports
+	if settings.framework == frameworkVitest {
+		imports = slices.Concat(vitestImports, imports)
+	}
+
+	for _, imported := range imports {
+		if !regexp.MustCompile(imported.usage).MatchString(source) {
+			continue
+		}
+
+		matches := specImportRegex.FindAllStringSubmatchIndex(source, -1)
+		found := slices.ContainsFunc(matches, func(match []int) bool {
+			return regexp.MustCompile(`\b` + imported.name + `\b`).MatchString(source[match[2]:match[3]])
+		})
+		if found {
+			continue
+		}
+
+		added := false
+		if !imported.isDefault {
+			for _, match := range matches {
+				names := specImportNamesRegex.FindStringSubmatchIndex(source[match[2]:match[3]])
+				if source[match[6]:match[7]] != imported.from || names == nil {
+					continue
+				}
+				// Only the names between the braces are rewritten
+				start, end := match[2]+names[2], match[2]+names[3]
+				list := strings.TrimRight(source[start:end], " \t\n,")
+				if strings.Contains(list, "\n") {
+					insert := start + len(list)
+					source = source[:insert] + ",\n" + format.indent(1) + imported.name + source[insert:]
+				} else {
+					// One-line imports stay sorted as the template writes them
+					sorted := []string{imported.name}
+					for _, name := range strings.Split(list, ",") {
+						if name = strings.TrimSpace(name); name != "" {
+							sorted = append(sorted, name)
+						}
+					}
+					slices.SortFunc(sorted, func(a, b string) int {
+						return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+					})
+					source = source[:start] + " " + strings.Join(sorted, ", ") + " " + source[end:]
+				}
+				added = true
+				break
+			}
+		}
+		if added {
+			continue
+		}
+
+		line := tsImport{[]string{imported.name}, imported.from}
+		printed := printNodes(format, 0, []tsNode{line})
+		if imported.isDefault {
+			printed = "import " + imported.name + " from " + format.stringLiteral(imported.from) + format.terminator() + "\n"
+		}
+
+		insert := -1
+		for _, match := range matches {
+			if !strings.HasPrefix(source[match[6]:match[7]], ".") {
+				insert = min(match[1]+1, len(source))
+			}
+		}
+		if insert < 0 {
+			// The first import, separated from the code below it
+			insert, printed = 0, printed+"\n"
+		}
+		source = source[:insert] + printed + source[insert:]
+	}
+	return source
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddSpecImports(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "Template imports",
+			source:   "import { TestBed } from '@angular/core/testing';\nimport { render } from '@testing-library/angular';\n\nimport { A } from './a';\n\nawait userEvent.click(screen.getByText('A'));\n",
+			expected: "import { TestBed } from '@angular/core/testing';\nimport { render, screen } from '@testing-library/angular';\nimport userEvent from '@testing-library/user-event';\n\nimport { A } from './a';\n\nawait userEvent.click(screen.getByText('A'));\n",
+		},
+		{
+			name:     "Multi-line import",
+			source:   "import {\n\trender,\n\twithin,\n} from '@testing-library/angular';\n\nexpect(screen.getByText('A')).toBeVisible();\n",
+			expected: "import {\n\trender,\n\twithin,\n\tscreen,\n} from '@testing-library/angular';\n\nexpect(screen.getByText('A')).toBeVisible();\n",
+		},
+		{
+			name:     "Already imported",
+			source:   "import { render, screen } from '@testing-library/angular';\nimport userEvent from '@testing-library/user-event';\n\nawait userEvent.click(screen.getByText('A'));\n",
+			expected: "import { render, screen } from '@testing-library/angular';\nimport userEvent from '@testing-library/user-event';\n\nawait userEvent.click(screen.getByText('A'));\n",
+		},
+		{
+			name:     "No imports",
+			source:   "expect(screen.getByText('A')).toBeVisible();\n",
+			expected: "import { screen } from '@testing-library/angular';\n\nexpect(screen.getByText('A')).toBeVisible();\n",
+		},
+		{
+			name:     "Unused",
+			source:   "import { render } from '@testing-library/angular';\n",
+			expected: "import { render } from '@testing-library/angular';\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := addSpecImports(tt.source, defaultSpecSettings()); result != tt.expected {
+				t.Errorf("addSpecImports() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPreconditionsVitestImports(t *testing.T) {
+	settings := defaultSpecSettings()
+	settings.framework = frameworkVitest
+	acs := "1. Given the user is an admin\na. Sees the settings"
+	expected := "import { beforeEach, describe, expect, it } from 'vitest';"
+
+	template := createTemplate("dashboard", settings)
+	document := parseAcText("", acs, settings.config.Acs)
+	generated := addSpecImports(integrateAcsWithTemplate(template, "DASH-1", renderAcs(document.Nodes, settings.forAcs(document))), settings)
+	if !strings.Contains(generated, expected) {
+		t.Errorf("Generated spec does not import beforeEach in:\n%s", generated)
+	}
+
+	merged, _, err := mergeAcs(template, parseAcText("", acs, acsConfig{}).Nodes, "DASH-1", settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(merged, expected) {
+		t.Errorf("Merged spec does not import beforeEach in:\n%s", merged)
+	}
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// jiraIssue is the part of a Jira REST issue that holds the ACs
+type jiraIssue struct {
+	Key    string                     `json:"key"`
+	Self   string                     `json:"self"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+// adfNode is a node of an Atlassian Document Format document
+type adfNode struct {
+	Type    string         `json:"type"`
+	Text    string         `json:"text"`
+	Attrs   map[string]any `json:"attrs"`
+	Content []adfNode      `json:"content"`
+}
+
+// isJiraIssue reports whether text is a Jira issue exported as JSON
+func isJiraIssue(text string) bool {
+	if !strings.HasPrefix(strings.TrimSpace(text), "{") {
+		return false
+	}
+	var issue jiraIssue
+	return json.Unmarshal([]byte(text), &issue) == nil && issue.Key != "" && issue.Fields != nil
+}
+
+// parseJiraIssue builds the AC tree from the description, or the configured
+// field, of a Jira issue. Rich text fields in Atlassian Document Format and
+// legacy wiki markup are both converted to Markdown first, so their
+// headings and lists keep their structure. The issue link is filled in from
+// the issue key.
+func parseJiraIssue(text string, config acsConfig) acDocument {
+	var issue jiraIssue
+	if err := json.Unmarshal([]byte(text), &issue); err != nil {
+		return acDocument{Diagnostics: []acDiagnostic{{1, "", "is not a valid Jira issue: " + err.Error()}}}
+	}
+
+	field := config.Jira.Field
+	if field == "" {
+		field = "description"
+	}
+
+	var markdown string
+	raw := issue.Fields[field]
+
+	var wiki string
+	var adf adfNode
+	switch {
+	case len(raw) == 0 || string(raw) == "null":
+		return acDocument{
+			Link:        jiraLink(issue),
+			Diagnostics: []acDiagnostic{{1, issue.Key, fmt.Sprintf("has no %s field", field)}},
+		}
+	case json.Unmarshal(raw, &wiki) == nil:
+		markdown = wikiToMarkdown(wiki)
+	case json.Unmarshal(raw, &adf) == nil:
+		markdown = adfToMarkdown(adf)
+	default:
+		return acDocument{
+			Link:        jiraLink(issue),
+			Diagnostics: []acDiagnostic{{1, issue.Key, fmt.Sprintf("has a %s field that isn't rich text", field)}},
+		}
+	}
+
+	document := parseMarkdown(markdown, config)
+	document.Link = jiraLink(issue)
+	return document
+}
+
+// jiraLink returns the browse URL of an issue, or its key when the export
+// doesn't say which site it comes from
+func jiraLink(issue jiraIssue) string {
+	self, err := url.Parse(issue.Self)
+	if err != nil || self.Host == "" || issue.Key == "" {
+		return issue.Key
+	}
+	return self.Scheme + "://" + self.Host + "/browse/" + issue.Key
+}
+
+// adfToMarkdown renders the blocks of an ADF document as Markdown
+func adfToMarkdown(doc adfNode) string {
+	var lines []string
+	for _, block := range doc.Content {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, adfBlock(block, "")...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// adfBlock renders a block node, each line prefixed with indent
+func adfBlock(node adfNode, indent string) []string {
+	var lines []string
+	prefixed := func(text string) {
+		for _, line := range strings.Split(text, "\n") {
+			lines = append(lines, indent+line)
+		}
+	}
+
+	switch node.Type {
+	case "heading":
+		level, _ := node.Attrs["level"].(float64)
+		prefixed(strings.Repeat("#", max(int(level), 1)) + " " + adfInline(node.Content))
+	case "paragraph", "codeBlock":
+		prefixed(adfInline(node.Content))
+	case "bulletList", "orderedList", "taskList":
+		for _, item := range node.Content {
+			lines = append(lines, adfListItem(item, indent)...)
+		}
+	case "rule":
+		lines = append(lines, indent+"---")
+	case "tableRow":
+		var cells []string
+		for _, cell := range node.Content {
+			var texts []string
+			for _, block := range cell.Content {
+				texts = append(texts, adfInline(block.Content))
+			}
+			cells = append(cells, strings.Join(texts, " "))
+		}
+		prefixed("| " + strings.Join(cells, " | ") + " |")
+	default:
+		// Containers such as panels, quotes, expands and tables
+		if title, ok := node.Attrs["title"].(string); ok && title != "" {
+			prefixed(title)
+		}
+		for _, child := range node.Content {
+			lines = append(lines, adfBlock(child, indent)...)
+		}
+	}
+
+	return lines
+}
+
+// adfListItem renders a list item, its first paragraph on the marker line and
+// anything else, nested lists included, indented under it
+func adfListItem(item adfNode, indent string) []string {
+	marker := "- "
+	switch {
+	case item.Type == "taskItem" && item.Attrs["state"] == "DONE":
+		marker = "- [x] "
+	case item.Type == "taskItem":
+		marker = "- [ ] "
+	}
+
+	// Task items hold their text directly
+	if item.Type == "taskItem" {
+		return []string{indent + marker + adfInline(item.Content)}
+	}
+
+	var lines []string
+	nested := indent + strings.Repeat(" ", len(marker))
+	for i, block := range item.Content {
+		if i == 0 && block.Type == "paragraph" {
+			text := strings.Split(adfInline(block.Content), "\n")
+			lines = append(lines, indent+marker+text[0])
+			for _, line := range text[1:] {
+				lines = append(lines, nested+line)
+			}
+			continue
+		}
+		if i == 0 {
+			lines = append(lines, indent+marker)
+		}
+		lines = append(lines, adfBlock(block, nested)...)
+	}
+	return lines
+}
+
+// adfInline renders inline nodes as plain text, hard breaks as new lines
+func adfInline(nodes []adfNode) string {
+	var result strings.Builder
+	for _, node := range nodes {
+		switch node.Type {
+		case "text":
+			result.WriteString(node.Text)
+		case "hardBreak":
+			result.WriteString("\n")
+		case "mention", "status", "date":
+			if text, ok := node.Attrs["text"].(string); ok {
+				result.WriteString(text)
+			}
+		case "emoji":
+			if text, ok := node.Attrs["text"].(string); ok {
+				result.WriteString(text)
+			} else if name, ok := node.Attrs["shortName"].(string); ok {
+				result.WriteString(name)
+			}
+		case "inlineCard":
+			if link, ok := node.Attrs["url"].(string); ok {
+				result.WriteString(link)
+			}
+		default:
+			result.WriteString(adfInline(node.Content))
+		}
+	}
+	return result.String()
+}
+
+var (
+	wikiHeadingRegex = regexp.MustCompile(`^h([1-6])\.\s+(.*)$`)
+	wikiListRegex    = regexp.MustCompile(`^([#*-]+)\s+(.*)$`)
+	wikiLinkRegex    = regexp.MustCompile(`\[(?:([^|\]]*)\|)?([^\]]*)\]`)
+	wikiMacroRegex   = regexp.MustCompile(`\{(?:code|noformat|quote|panel|color)(?::[^}]*)?\}`)
+)
+
+// wikiToMarkdown converts Jira wiki markup headings and lists, where nesting
+// is written by repeating the markers (# ## #*), to indented Markdown
+func wikiToMarkdown(text string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = wikiMacroRegex.ReplaceAllString(line, "")
+		line = wikiLinkRegex.ReplaceAllStringFunc(line, func(link string) string {
+			matches := wikiLinkRegex.FindStringSubmatch(link)
+			if matches[1] != "" {
+				return matches[1]
+			}
+			return matches[2]
+		})
+		trimmed := strings.TrimSpace(line)
+
+		if matches := wikiHeadingRegex.FindStringSubmatch(trimmed); matches != nil {
+			lines = append(lines, strings.Repeat("#", int(matches[1][0]-'0'))+" "+matches[2])
+			continue
+		}
+
+		if matches := wikiListRegex.FindStringSubmatch(trimmed); matches != nil && !markdownBreakRegex.MatchString(trimmed) {
+			lines = append(lines, strings.Repeat("  ", len(matches[1])-1)+"- "+matches[2])
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+const adfIssue = `{
+	"key": "DASH-12",
+	"self": "https://example.atlassian.net/rest/api/3/issue/10012",
+	"fields": {
+		"summary": "Dashboard widgets",
+		"description": {
+			"type": "doc",
+			"version": 1,
+			"content": [
+				{"type": "heading", "attrs": {"level": 2}, "content": [{"type": "text", "text": "Widgets"}]},
+				{"type": "paragraph", "content": [{"type": "text", "text": "Agreed with "}, {"type": "mention", "attrs": {"text": "@Sam"}}]},
+				{"type": "orderedList", "content": [
+					{"type": "listItem", "content": [
+						{"type": "paragraph", "content": [{"type": "text", "text": "Revenue", "marks": [{"type": "strong"}]}]},
+						{"type": "bulletList", "content": [
+							{"type": "listItem", "content": [{"type": "paragraph", "content": [
+								{"type": "text", "text": "Shows the total"},
+								{"type": "hardBreak"},
+								{"type": "text", "text": "in the account currency"}
+							]}]}
+						]}
+					]},
+					{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Orders"}]}]}
+				]},
+				{"type": "taskList", "content": [
+					{"type": "taskItem", "attrs": {"state": "DONE"}, "content": [{"type": "text", "text": "Saves layout"}]}
+				]}
+			]
+		}
+	}
+}`
+
+func TestParseJiraIssue(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		config   acsConfig
+		expected string
+		link     string
+	}{
+		{
+			name:     "ADF description",
+			text:     adfIssue,
+			expected: "Widgets(Revenue(Shows the total) Orders Saves layout)",
+			link:     "https://example.atlassian.net/browse/DASH-12",
+		},
+		{
+			name:     "Wiki markup custom field",
+			text:     `{"key": "DASH-13", "fields": {"customfield_10042": "h2. Widgets\n# Revenue\n## Shows the [total|https://example.com]\n#* In the account currency\n# Orders\n----\n* Saves layout"}}`,
+			config:   acsConfig{Jira: jiraConfig{Field: "customfield_10042"}},
+			expected: "Widgets(Revenue(Shows the total In the account currency) Orders Saves layout)",
+			link:     "DASH-13",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !isJiraIssue(tt.text) {
+				t.Fatal("isJiraIssue() = false, want true")
+			}
+
+			document := parseJiraIssue(tt.text, tt.config)
+			if result := outlineShape(document.Nodes); result != tt.expected {
+				t.Errorf("parseJiraIssue() = %q, want %q", result, tt.expected)
+			}
+			if document.Link != tt.link {
+				t.Errorf("parseJiraIssue() link = %q, want %q", document.Link, tt.link)
+			}
+			if len(document.Diagnostics) > 0 {
+				t.Errorf("parseJiraIssue() diagnostics = %+v, want none", document.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestParseJiraIssueNotes(t *testing.T) {
+	document := parseJiraIssue(adfIssue, acsConfig{})
+
+	widgets := document.Nodes[0]
+	if !reflect.DeepEqual(widgets.Notes, []string{"Agreed with @Sam"}) {
+		t.Errorf("Expected the paragraph as notes, got %q", widgets.Notes)
+	}
+
+	total := widgets.Children[0].Children[0]
+	if !reflect.DeepEqual(total.Notes, []string{"in the account currency"}) {
+		t.Errorf("Expected the line after the hard break as notes, got %q", total.Notes)
+	}
+}
+
+func TestParseJiraIssueMissingField(t *testing.T) {
+	document := parseJiraIssue(`{"key": "DASH-14", "fields": {"description": null}}`, acsConfig{})
+
+	expected := []acDiagnostic{{1, "DASH-14", "has no description field"}}
+	if !reflect.DeepEqual(document.Diagnostics, expected) || document.Link != "DASH-14" {
+		t.Errorf("parseJiraIssue() = %+v, want diagnostics %+v", document, expected)
+	}
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownHeadingRegex = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	markdownSetextRegex  = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	markdownBreakRegex   = regexp.MustCompile(`^ {0,3}([-*_])(?:\s*[-*_]){2,}\s*$`)
+	markdownLinkRegex    = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	markdownStrongRegex  = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	markdownEmRegex      = regexp.MustCompile(`(^|[^\w*])[*_]([^*_\s][^*_]*)[*_]`)
+	markdownCodeRegex    = regexp.MustCompile("`([^`]*)`")
+)
+
+// isMarkdown reports whether ACs are a Markdown document rather than a
+// plain outline with a line that reads as a heading: a heading with list
+// items below it and none above it, or headings of two levels
+func isMarkdown(text string) bool {
+	levels := make(map[int]bool)
+	items := false
+	for _, line := range strings.Split(text, "\n") {
+		if matches := markdownHeadingRegex.FindStringSubmatch(line); matches != nil {
+			levels[len(matches[1])] = true
+			if len(levels) > 1 {
+				return true
+			}
+			continue
+		}
+		if isOutlineItem(strings.TrimSpace(line)) {
+			if len(levels) > 0 && !items {
+				return true
+			}
+			items = true
+		}
+	}
+	return false
+}
+
+// parseMarkdown builds the AC tree from a Markdown document. Headings become
+// groups nested by their level, even without items below them, the lists
+// below each heading are read like a plain outline, and paragraphs before
+// the first list are the heading's notes.
+func parseMarkdown(text string, config acsConfig) acDocument {
+	lines := strings.Split(text, "\n")
+	skipFrontMatter(lines)
+
+	type heading struct {
+		depth int
+		node  *acNode
+	}
+
+	var document acDocument
+	var headings []heading
+	var owner *acNode
+	preamble := false
+
+	// Lines of the current section, other lines blank so that line numbers
+	// are kept when the section is parsed
+	section := make([]string, len(lines))
+
+	flush := func() {
+		outline := parseAcOutline(strings.Join(section, "\n"), config)
+		document.Diagnostics = append(document.Diagnostics, outline.Diagnostics...)
+
+		for _, node := range outline.Nodes {
+			if owner == nil {
+				document.Nodes = append(document.Nodes, node)
+				continue
+			}
+			shiftLevels(node, owner.Level)
+			owner.Children = append(owner.Children, node)
+		}
+
+		section = make([]string, len(lines))
+	}
+
+	for index := 0; index < len(lines); index++ {
+		line := lines[index]
+		trimmed := strings.TrimSpace(line)
+
+		depth, title := 0, ""
+		if matches := markdownHeadingRegex.FindStringSubmatch(line); matches != nil {
+			depth, title = len(matches[1]), matches[2]
+		} else if index+1 < len(lines) && trimmed != "" && !isOutlineItem(trimmed) && markdownSetextRegex.MatchString(lines[index+1]) {
+			depth, title = 2, trimmed
+			if strings.Contains(lines[index+1], "=") {
+				depth = 1
+			}
+		}
+
+		if depth > 0 {
+			flush()
+
+			for len(headings) > 0 && headings[len(headings)-1].depth >= depth {
+				headings = headings[:len(headings)-1]
+			}
+
+			node := &acNode{Title: markdownInline(title), Line: index + 1, Level: len(headings) + 1, Kind: acKindSection}
+			if len(headings) == 0 {
+				document.Nodes = append(document.Nodes, node)
+			} else {
+				parent := headings[len(headings)-1].node
+				parent.Children = append(parent.Children, node)
+			}
+
+			headings = append(headings, heading{depth, node})
+			owner, preamble = node, true
+
+			if !markdownHeadingRegex.MatchString(line) {
+				// Skip the setext underline
+				index++
+			}
+			continue
+		}
+
+		if markdownBreakRegex.MatchString(line) {
+			continue
+		}
+
+		if preamble && trimmed != "" && !isOutlineItem(trimmed) {
+			owner.Notes = append(owner.Notes, markdownInline(trimmed))
+			continue
+		}
+		if trimmed != "" {
+			preamble = false
+		}
+
+		section[index] = line
+	}
+	flush()
+
+	for _, node := range document.Nodes {
+		walkAcNodes(node, func(node *acNode) {
+			node.Title = markdownInline(node.Title)
+			for i, note := range node.Notes {
+				node.Notes[i] = markdownInline(note)
+			}
+			if node.Examples != nil {
+				for _, row := range append([][]string{node.Examples.Header}, node.Examples.Rows...) {
+					for i, cell := range row {
+						row[i] = markdownInline(cell)
+					}
+				}
+			}
+		})
+	}
+
+	return document
+}
+
+// skipFrontMatter blanks a leading YAML front matter block
+func skipFrontMatter(lines []string) {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			for j := 0; j <= i; j++ {
+				lines[j] = ""
+			}
+			return
+		}
+	}
+}
+
+func isOutlineItem(line string) bool {
+	_, _, ok := parseOutlineItem(line)
+	return ok
+}
+
+// markdownInline strips links, emphasis and code spans down to their text
+func markdownInline(text string) string {
+	text = markdownLinkRegex.ReplaceAllString(text, "$1")
+	text = markdownStrongRegex.ReplaceAllString(text, "$2")
+	text = markdownEmRegex.ReplaceAllString(text, "$1$2")
+	return markdownCodeRegex.ReplaceAllString(text, "$1")
+}
+
+// shiftLevels moves a subtree down by offset levels
+func shiftLevels(node *acNode, offset int) {
+	walkAcNodes(node, func(node *acNode) {
+		node.Level += offset
+	})
+}
+
+// walkAcNodes calls fn for a node and all of its descendants
+func walkAcNodes(node *acNode, fn func(*acNode)) {
+	fn(node)
+	for _, child := range node.Children {
+		walkAcNodes(child, fn)
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		outline  bool
+		expected string
+	}{
+		{
+			name:     "Headings and lists",
+			text:     "# Dashboard\n\n## Widgets\n\n1. Revenue\n   a. Currency format\n2. Orders\n\n## Settings\n\n- Saves layout\n",
+			expected: "Dashboard(Widgets(Revenue(Currency format) Orders) Settings(Saves layout))",
+		},
+		{
+			name:     "Heading levels may be skipped",
+			text:     "# Dashboard\n### Widgets\n- Revenue\n## Settings\n- Saves layout",
+			expected: "Dashboard(Widgets(Revenue) Settings(Saves layout))",
+		},
+		{
+			name:     "Setext headings and task lists",
+			text:     "Dashboard\n=========\n\nSettings\n--------\n- [ ] Saves layout\n- [x] Resets layout",
+			expected: "Dashboard(Settings(Saves layout Resets layout))",
+		},
+		{
+			name:     "Front matter and thematic breaks",
+			text:     "---\ntitle: Dashboard\n---\n# Dashboard\n- Revenue\n\n---\n\n- Orders",
+			expected: "Dashboard(Revenue Orders)",
+		},
+		{
+			name:     "Inline formatting",
+			text:     "# The **dashboard**\n- Shows the *revenue* in `EUR` from [reports](https://example.com)",
+			expected: "The dashboard(Shows the revenue in EUR from reports)",
+		},
+		{
+			name:     "Headings without items",
+			text:     "# Dashboard\n## Widgets\n## Settings\n- Saves layout",
+			expected: "Dashboard(Widgets() Settings(Saves layout))",
+		},
+		{
+			name:     "Plain outline with a heading line",
+			text:     "1. Dashboard\n   a. Revenue\n# Notes\n2. Orders",
+			outline:  true,
+			expected: "Dashboard(Revenue) Orders",
+		},
+		{
+			name:     "Heading line without items",
+			text:     "# Dashboard",
+			outline:  true,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.outline {
+				if isMarkdown(tt.text) {
+					t.Errorf("isMarkdown(%q) = true, want false", tt.text)
+				}
+				if result := outlineShape(parseAcText("", tt.text, acsConfig{}).Nodes); result != tt.expected {
+					t.Errorf("parseAcText() = %q, want %q", result, tt.expected)
+				}
+				return
+			}
+
+			if result := outlineShape(parseMarkdown(tt.text, acsConfig{}).Nodes); result != tt.expected {
+				t.Errorf("parseMarkdown() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseMarkdownMetadata(t *testing.T) {
+	document := parseMarkdown("# Dashboard\n\nShows the widgets.\n\n## Widgets\n\n1. Revenue\n\nStray paragraph", acsConfig{})
+
+	dashboard := document.Nodes[0]
+	if !reflect.DeepEqual(dashboard.Notes, []string{"Shows the widgets."}) {
+		t.Errorf("Expected the paragraph as notes, got %q", dashboard.Notes)
+	}
+
+	revenue := dashboard.Children[0].Children[0]
+	if revenue.Level != 3 || revenue.Line != 7 {
+		t.Errorf("Expected level 3 on line 7, got level %d on line %d", revenue.Level, revenue.Line)
+	}
+
+	expected := []acDiagnostic{{9, "Stray paragraph", "is not a list item"}}
+	if !reflect.DeepEqual(document.Diagnostics, expected) {
+		t.Errorf("parseMarkdown() diagnostics = %+v, want %+v", document.Diagnostics, expected)
+	}
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// specEdit replaces length bytes of a spec at offset with text
+type specEdit struct {
+	offset, length int
+	text           string
+}
+
+// applySpecEdits applies edits that don't overlap, starting from the end so
+// that offsets stay valid
+func applySpecEdits(source string, edits []specEdit) string {
+	slices.SortFunc(edits, func(a, b specEdit) int {
+		return b.offset - a.offset
+	})
+	for _, edit := range edits {
+		source = source[:edit.offset] + edit.text + source[edit.offset+edit.length:]
+	}
+	return source
+}
+
+// mergeTestFile adds the tests for ACs that aren't in the spec at filePath
+// yet, leaving the rest of the file untouched
+func mergeTestFile(filePath string, nodes []*acNode, acsLink string, settings specSettings) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	merged, added, err := mergeAcs(string(data), nodes, acsLink, settings)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filePath, []byte(merged), 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("\033[32m Merged %d new test(s) into %s \033[0m\n", added, filePath)
+	return nil
+}
+
+// mergeAcs inserts the AC nodes that have no test yet into an existing spec,
+// each at the end of the describe block of its group, and adds the ACs link
+// to the header. It returns the merged spec and the number of tests added.
+func mergeAcs(source string, nodes []*acNode, acsLink string, settings specSettings) (string, int, error) {
+	var component *specBlock
+	for _, block := range parseSpecBlocks(source) {
+		if block.isDescribe() && block.BodyStart >= 0 {
+			component = block
+			break
+		}
+	}
+	if component == nil {
+		return "", 0, fmt.Errorf("no describe block to merge the ACs into")
+	}
+
+	var edits []specEdit
+	added := mergeAcNodes(source, component, nodes, 1, settings, &edits)
+
+	merged := addSpecImports(applySpecEdits(source, edits), settings)
+	return addAcsLink(merged, acsLink), added, nil
+}
+
+// mergeAcNodes collects the inserts for the nodes missing from a describe
+// block, descending into the groups it already has
+func mergeAcNodes(source string, container *specBlock, nodes []*acNode, level int, settings specSettings, edits *[]specEdit) int {
+	var missing []*acNode
+	added := 0
+
+	for _, node := range nodes {
+		// Setup of existing describe blocks is hand-written by now
+		if node.Kind == acKindBackground {
+			continue
+		}
+
+		existing := findSpecBlock(container.Children, node, settings)
+		if existing == nil {
+			missing = append(missing, node)
+			walkAcNodes(node, func(n *acNode) {
+				if !n.isGroup() && n.Kind != acKindBackground {
+					added++
+				}
+			})
+			continue
+		}
+
+		if node.isGroup() && existing.BodyStart >= 0 {
+			children := settings
+			// Tests of a precondition describe use the component its hook mounts
+			if strings.Contains(source[existing.BodyStart:existing.BodyEnd], "let "+acsMountedVariable+":") {
+				children.mounted = acsMountedVariable
+			}
+			added += mergeAcNodes(source, existing, node.Children, level+1, children, edits)
+		}
+	}
+
+	if len(missing) == 0 {
+		return added
+	}
+
+	blocks := printNodes(settings.format, level, separated(renderAcNodes(missing, settings)...))
+	lineStart := strings.LastIndexByte(source[:container.BodyEnd], '\n') + 1
+
+	switch {
+	case strings.TrimSpace(source[lineStart:container.BodyEnd]) != "":
+		// The describe block closes on the line it opens
+		*edits = append(*edits, specEdit{container.BodyEnd, 0, "\n" + blocks + settings.format.indent(level-1)})
+	case strings.HasSuffix(strings.TrimSpace(source[:lineStart]), "{"):
+		*edits = append(*edits, specEdit{lineStart, 0, blocks})
+	default:
+		*edits = append(*edits, specEdit{lineStart, 0, "\n" + blocks})
+	}
+	return added
+}
+
+// findSpecBlock returns the block generated for an AC node, matching titles
+// regardless of case, spacing and the names of <placeholders> and of the
+// values formatted into them
+func findSpecBlock(blocks []*specBlock, node *acNode, settings specSettings) *specBlock {
+	title := acsTestName(node, settings)
+	if node.isGroup() {
+		title = acsDescribeTitle(node, settings)
+	}
+
+	for _, block := range blocks {
+		if block.isDescribe() == node.isGroup() && mergeKey(block.Title) == mergeKey(title) {
+			return block
+		}
+	}
+	return nil
+}
+
+// Values formatted into it.each titles, which Jasmine loops interpolate
+var eachFormatRegex = regexp.MustCompile(`%[sdifjop#]`)
+
+func mergeKey(title string) string {
+	title = eachFormatRegex.ReplaceAllString(examplePlaceholderRegex.ReplaceAllString(title, "<>"), "<>")
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+// addAcsLink lists a link under "ACs from" in the header of a spec, in
+// place of the placeholder or after the links already there. Specs without
+// the header get one above their component's describe block.
+func addAcsLink(source, acsLink string) string {
+	if acsLink == "" {
+		return source
+	}
+
+	acsLink = escapeJsDoc(acsLink)
+	if strings.Contains(source, acsLinkPlaceholder) {
+		return strings.Replace(source, acsLinkPlaceholder, acsLink, 1)
+	}
+
+	lines := strings.SplitAfter(source, "\n")
+	for i, line := range lines {
+		if !strings.Contains(line, "ACs from:") {
+			continue
+		}
+
+		prefix, last := " *  - ", i
+		for j := i + 1; j < len(lines); j++ {
+			matches := traceLinkRegex.FindStringSubmatch(lines[j])
+			if matches == nil {
+				break
+			}
+			if matches[1] == acsLink {
+				return source
+			}
+			prefix, last = lines[j][:strings.Index(lines[j], "-")+2], j
+		}
+
+		return strings.Join(slices.Insert(lines, last+1, prefix+acsLink+"\n"), "")
+	}
+
+	for _, block := range parseSpecBlocks(source) {
+		if block.isDescribe() {
+			lineStart := strings.LastIndexByte(source[:block.Start], '\n') + 1
+			header := printNodes(formatOptions{}, 0, []tsNode{tsDocComment{"ACs from:", " - " + acsLink}})
+			return source[:lineStart] + header + source[lineStart:]
+		}
+	}
+	return source
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeAcs(t *testing.T) {
+	settings := defaultSpecSettings()
+	settings.config.Snippets.DisableDefaults = true
+	document := parseAcText("", "1. Widgets\na. Shows revenue", settings.config.Acs)
+	existing := integrateAcsWithTemplate(createTemplate("dashboard", settings), "DASH-1", renderAcs(document.Nodes, settings.forAcs(document)))
+	// A test implemented since the spec was generated
+	existing = strings.Replace(existing, "// TODO: Implement test", "expect(screen.getByText('Revenue')).toBeVisible();", 1)
+
+	nodes := parseAcText("", "1. Widgets\na. Shows  REVENUE\nb. Shows orders\n2. Settings\na. Saves the layout", acsConfig{}).Nodes
+	merged, added, err := mergeAcs(existing, nodes, "DASH-2", settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if added != 2 {
+		t.Errorf("mergeAcs() added %d tests, want 2", added)
+	}
+
+	expectedPhrases := []string{
+		" *  - DASH-1\n *  - DASH-2\n */",
+		"\t\t\texpect(screen.getByText('Revenue')).toBeVisible();\n\t\t}); // " + acHashComment(&acNode{Title: "Shows revenue"}) + "\n\n\t\tit('should show orders', async () => {\n",
+		"\t});\n\n\tdescribe('Settings', () => {\n\t\tit('should save the layout', async () => {\n",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(merged, phrase) {
+			t.Errorf("mergeAcs() does not contain expected phrase %q in:\n%s", phrase, merged)
+		}
+	}
+	if count := strings.Count(merged, "should show revenue"); count != 1 {
+		t.Errorf("mergeAcs() should keep the existing test only, found it %d times", count)
+	}
+
+	again, added, _ := mergeAcs(merged, nodes, "DASH-2", settings)
+	if added != 0 || again != merged {
+		t.Errorf("Merging the same ACs twice should change nothing, added %d", added)
+	}
+}
+
+func TestMergeAcsSingleLineDescribe(t *testing.T) {
+	settings := defaultSpecSettings()
+	settings.config.Snippets.DisableDefaults = true
+	nodes := parseAcText("", "1. Shows revenue", acsConfig{}).Nodes
+
+	merged, _, err := mergeAcs("describe('DashboardComponent', () => {});\n", nodes, "", settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "describe('DashboardComponent', () => {\n" +
+		"\tit('should show revenue', async () => {\n" +
+		"\t\tconst { view, httpTestingController, loader } = await mount();\n" +
+		"\t\t// TODO: Implement test\n" +
+		"\t}); // " + acHashComment(nodes[0]) + "\n" +
+		"});\n"
+	if merged != expected {
+		t.Errorf("mergeAcs() = %q, want %q", merged, expected)
+	}
+
+	if _, _, err := mergeAcs("const a = 1;\n", nodes, "", settings); err == nil {
+		t.Error("mergeAcs() should fail without a describe block")
+	}
+}
+
+func TestMergeAcsPrecondition(t *testing.T) {
+	settings := defaultSpecSettings()
+	document := parseAcText("", "1. Given the user is an admin\na. Sees the settings", settings.config.Acs)
+	existing := integrateAcsWithTemplate(createTemplate("dashboard", settings), "", renderAcs(document.Nodes, settings.forAcs(document)))
+
+	nodes := parseAcText("", "1. Given the user is an admin\na. Sees the settings\nb. Deletes users", acsConfig{}).Nodes
+	merged, added, err := mergeAcs(existing, nodes, "", settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if added != 1 {
+		t.Errorf("mergeAcs() added %d tests, want 1", added)
+	}
+	expected := "\t\tit('should delete users', async () => {\n\t\t\tconst { view, httpTestingController, loader } = mounted;\n"
+	if !strings.Contains(merged, expected) {
+		t.Errorf("mergeAcs() does not contain expected phrase %q in:\n%s", expected, merged)
+	}
+}
+
+func TestMergeAcsTables(t *testing.T) {
+	acs := "1. Applies the discount\n| quantity | total |\n| 1 | 10 |"
+
+	for _, framework := range []testFramework{frameworkJest, frameworkKarma} {
+		settings := defaultSpecSettings()
+		settings.framework = framework
+		document := parseAcText("", acs, settings.config.Acs)
+		existing := integrateAcsWithTemplate(createTemplate("cart", settings), "", renderAcs(document.Nodes, settings.forAcs(document)))
+
+		merged, added, err := mergeAcs(existing, parseAcText("", acs, acsConfig{}).Nodes, "", settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if added != 0 || merged != existing {
+			t.Errorf("mergeAcs() with %s should find the table test, added %d", framework, added)
+		}
+	}
+}
+
+func TestAddAcsLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		link     string
+		expected string
+	}{
+		{
+			name:     "Placeholder",
+			source:   "/**\n * ACs from:\n *  - " + acsLinkPlaceholder + "\n */",
+			link:     "DASH-1",
+			expected: "/**\n * ACs from:\n *  - DASH-1\n */",
+		},
+		{
+			name:     "Existing link",
+			source:   "/**\n * ACs from:\n *  - DASH-1\n */",
+			link:     "DASH-1",
+			expected: "/**\n * ACs from:\n *  - DASH-1\n */",
+		},
+		{
+			name:     "No header",
+			source:   "import { A } from './a';\n\ndescribe('A', () => {});",
+			link:     "DASH-1",
+			expected: "import { A } from './a';\n\n/**\n * ACs from:\n *  - DASH-1\n */\ndescribe('A', () => {});",
+		},
+		{
+			name:     "No header or describe",
+			source:   "it('works', () => {});",
+			link:     "DASH-1",
+			expected: "it('works', () => {});",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := addAcsLink(tt.source, tt.link); result != tt.expected {
+				t.Errorf("addAcsLink() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// acNode is a single acceptance criterion. Nodes with children become
+// describe blocks, leaves become tests.
+type acNode struct {
+	Title  string `json:"title" yaml:"title"`
+	Marker string `json:"marker,omitempty" yaml:"marker,omitempty"`
+	Level  int    `json:"level" yaml:"level"`
+	Line   int    `json:"line,omitempty" yaml:"line,omitempty"`
+	// Kind is set for nodes read from Gherkin, which uses the keyword, and
+	// for the headings of Markdown documents and structured suites
+	Kind string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	// ID is the test case ID in a test management tool
+	ID       string   `json:"id,omitempty" yaml:"id,omitempty"`
+	Priority string   `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Skip     bool     `json:"skip,omitempty" yaml:"skip,omitempty"`
+	// Notes are the unnumbered lines that follow the title
+	Notes         []string  `json:"notes,omitempty" yaml:"notes,omitempty"`
+	Preconditions []string  `json:"preconditions,omitempty" yaml:"preconditions,omitempty"`
+	Steps         []acStep  `json:"steps,omitempty" yaml:"steps,omitempty"`
+	Examples      *acTable  `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Children      []*acNode `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// Kinds of Gherkin nodes, of the suites of structured AC files and of the
+// sections of Markdown documents
+const (
+	acKindFeature    = "feature"
+	acKindRule       = "rule"
+	acKindSuite      = "suite"
+	acKindSection    = "section"
+	acKindBackground = "background"
+	acKindScenario   = "scenario"
+)
+
+// Keywords of imported test case steps
+const (
+	stepAction   = "Step"
+	stepExpected = "Expected"
+)
+
+// acStep is a Given/When/Then step of a scenario, or a step or expected
+// result of an imported test case
+type acStep struct {
+	Keyword string `json:"keyword" yaml:"keyword"`
+	Text    string `json:"text" yaml:"text"`
+}
+
+// acTable holds the examples a criterion is tested with, one test per row
+type acTable struct {
+	Header []string   `json:"header" yaml:"header"`
+	Rows   [][]string `json:"rows" yaml:"rows"`
+}
+
+func (n *acNode) isGroup() bool {
+	return len(n.Children) > 0 || n.Kind == acKindFeature || n.Kind == acKindRule || n.Kind == acKindSuite || n.Kind == acKindSection
+}
+
+// acDocument is the result of parsing ACs: the tree and the lines that
+// couldn't be placed in it
+type acDocument struct {
+	// Link points to the ACs when the source names them, such as a Jira issue
+	Link string `json:"link,omitempty" yaml:"link,omitempty"`
+	// Language is the language the ACs are written in, when they declare it
+	Language    string         `json:"language,omitempty" yaml:"language,omitempty"`
+	Nodes       []*acNode      `json:"nodes" yaml:"nodes"`
+	Diagnostics []acDiagnostic `json:"diagnostics,omitempty" yaml:"diagnostics,omitempty"`
+}
+
+// acDiagnostic reports an AC line that was left out of the tree
+type acDiagnostic struct {
+	Line   int    `json:"line" yaml:"line"`
+	Text   string `json:"text" yaml:"text"`
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+func (d acDiagnostic) String() string {
+	return fmt.Sprintf("line %d: %q %s", d.Line, d.Text, d.Reason)
+}
+
+// markerKind identifies a numbering style together with its delimiter, so
+// that "1." and "1)" lists are told apart
+type markerKind string
+
+// outlineMarker is the marker in front of an outline item
+type outlineMarker struct {
+	value     string // "1", "a", "1.2", or the bullet character
+	delimiter string // ".", ")" or "()" for numbered items
+	outline   bool   // decimal outline such as 1.2.3
+	bullet    bool
+	checkbox  string // the box content of a checkbox item, " " or "x"
+}
+
+var (
+	outlineItemRegex = regexp.MustCompile(`^(?:\(([A-Za-z]+|\d+)\)|(\d+(?:\.\d+)+)\.?|([A-Za-z]+|\d+)([.)])|([-*+•◦▪])(?:\s+\[([ xX])\])?|\[([ xX])\])\s+(.+)$`)
+	romanRegex       = regexp.MustCompile(`^m{0,4}(cm|cd|d?c{0,3})(xc|xl|l?x{0,3})(ix|iv|v?i{0,3})$`)
+)
+
+// parseOutlineItem splits a trimmed line into its marker and title
+func parseOutlineItem(line string) (outlineMarker, string, bool) {
+	matches := outlineItemRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return outlineMarker{}, "", false
+	}
+
+	title := strings.TrimSpace(matches[8])
+
+	switch {
+	case matches[1] != "":
+		return outlineMarker{value: matches[1], delimiter: "()"}, title, true
+	case matches[2] != "":
+		return outlineMarker{value: matches[2], outline: true}, title, true
+	case matches[3] != "":
+		return outlineMarker{value: matches[3], delimiter: matches[4]}, title, true
+	case matches[5] != "":
+		return outlineMarker{value: matches[5], bullet: true, checkbox: matches[6]}, title, true
+	default:
+		return outlineMarker{bullet: true, checkbox: matches[7]}, title, true
+	}
+}
+
+// String returns the marker as written in the outline
+func (m outlineMarker) String() string {
+	switch {
+	case m.checkbox != "":
+		return strings.TrimSpace(m.value + " [" + m.checkbox + "]")
+	case m.bullet:
+		return m.value
+	case m.outline:
+		return m.value
+	case m.delimiter == "()":
+		return "(" + m.value + ")"
+	default:
+		return m.value + m.delimiter
+	}
+}
+
+// outlineEntry is an open outline item, kept on a stack while parsing
+type outlineEntry struct {
+	indent int
+	kind   markerKind
+	value  string
+	node   *acNode
+}
+
+// parseAcOutline builds the AC tree from a numbered or bulleted outline.
+// Nesting follows indentation; items at the same indentation nest when their
+// numbering style differs from the enclosing item, so unindented outlines
+// such as "1." / "a." / "i." still produce a tree.
+//
+// Text lines directly below an item, or indented under it, are kept as notes
+// of that item, as are indented items using a disabled scheme. A table in the
+// notes of a criterion becomes its examples.
+//
+// Documents whose lists nest explicitly, such as HTML, Word or Jira issues,
+// are converted to lists that all use the "- " marker, so that indentation
+// alone decides the structure and no marker change nests items by accident.
+func parseAcOutline(text string, config acsConfig) acDocument {
+	var document acDocument
+	var stack []outlineEntry
+
+	var last *acNode
+	lastIndent := 0
+	adjacent := false
+
+	for index, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			adjacent = false
+			continue
+		}
+
+		indent := indentationWidth(line)
+		indented := last != nil && indent > lastIndent
+
+		marker, title, ok := parseOutlineItem(trimmed)
+		if !ok {
+			if indented || (last != nil && adjacent) {
+				last.Notes = append(last.Notes, trimmed)
+				adjacent = true
+				continue
+			}
+			document.Diagnostics = append(document.Diagnostics, acDiagnostic{index + 1, trimmed, "is not a list item"})
+			adjacent = false
+			continue
+		}
+
+		kind, reason := classifyMarker(marker, indent, stack, config)
+		if reason != "" {
+			if indented {
+				last.Notes = append(last.Notes, trimmed)
+				adjacent = true
+				continue
+			}
+			document.Diagnostics = append(document.Diagnostics, acDiagnostic{index + 1, trimmed, reason})
+			adjacent = false
+			continue
+		}
+
+		// With checkboxes disabled the box is part of the title
+		if marker.checkbox != "" && !config.allowsScheme(schemeCheckbox) {
+			title = "[" + marker.checkbox + "] " + title
+			marker.checkbox = ""
+		}
+
+		stack = popToParent(stack, indent, kind)
+
+		node := &acNode{
+			Title:  title,
+			Marker: marker.String(),
+			Line:   index + 1,
+			Level:  len(stack) + 1,
+		}
+
+		if len(stack) == 0 {
+			document.Nodes = append(document.Nodes, node)
+		} else {
+			parent := stack[len(stack)-1].node
+			parent.Children = append(parent.Children, node)
+		}
+
+		stack = append(stack, outlineEntry{indent: indent, kind: kind, value: marker.value, node: node})
+		last, lastIndent, adjacent = node, indent, true
+	}
+
+	extractTables(document.Nodes)
+	return document
+}
+
+// popToParent removes the entries that can't contain an item with the given
+// indentation and numbering style
+func popToParent(stack []outlineEntry, indent int, kind markerKind) []outlineEntry {
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+
+		if top.indent > indent {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if top.indent < indent {
+			break
+		}
+
+		// Same indentation: a sibling shares the numbering style
+		if top.kind == kind {
+			stack = stack[:len(stack)-1]
+			break
+		}
+
+		// Otherwise the item is a sibling of an enclosing item with its style,
+		// or the first child of the top item
+		if !hasOpenList(stack[:len(stack)-1], indent, kind) {
+			break
+		}
+		stack = stack[:len(stack)-1]
+	}
+
+	return stack
+}
+
+func hasOpenList(stack []outlineEntry, indent int, kind markerKind) bool {
+	for _, entry := range stack {
+		if entry.indent == indent && entry.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyMarker decides the numbering style of a marker, or returns the
+// reason it can't be used. Letters that are also roman numerals (i, v, x,
+// ...) are read as letters when they continue an alphabetic list and as
+// roman numerals otherwise.
+func classifyMarker(marker outlineMarker, indent int, stack []outlineEntry, config acsConfig) (markerKind, string) {
+	disabled := func(scheme string) string {
+		return fmt.Sprintf("uses the %s marker, which is disabled in %s", scheme, configFileName)
+	}
+
+	if marker.bullet {
+		scheme := schemeBullet
+		if marker.checkbox != "" && config.allowsScheme(schemeCheckbox) {
+			scheme = schemeCheckbox
+		}
+		if !config.allowsScheme(scheme) {
+			return "", disabled(scheme)
+		}
+		// Plain and checkbox items belong to one list whatever their bullet,
+		// as bullets differ only in looks
+		return markerKind(schemeBullet), ""
+	}
+
+	if marker.outline {
+		if !config.allowsScheme(schemeOutline) {
+			return "", disabled(schemeOutline)
+		}
+		depth := strings.Count(marker.value, ".") + 1
+		return markerKind(schemeOutline + strconv.Itoa(depth)), ""
+	}
+
+	if !config.allowsDelimiter(marker.delimiter) {
+		return "", fmt.Sprintf("uses the %q delimiter, which is disabled in %s", marker.delimiter, configFileName)
+	}
+
+	kindOf := func(scheme string) markerKind {
+		return markerKind(scheme + marker.delimiter)
+	}
+
+	value := marker.value
+	if value[0] >= '0' && value[0] <= '9' {
+		if !config.allowsScheme(schemeDecimal) {
+			return "", disabled(schemeDecimal)
+		}
+		return kindOf(schemeDecimal), ""
+	}
+
+	alpha, roman := schemeLowerAlpha, schemeLowerRoman
+	switch value {
+	case strings.ToLower(value):
+	case strings.ToUpper(value):
+		alpha, roman = schemeUpperAlpha, schemeUpperRoman
+	default:
+		return "", fmt.Sprintf("has an unrecognised marker %q", marker)
+	}
+
+	lower := strings.ToLower(value)
+	validRoman := romanRegex.MatchString(lower)
+	validLetter := len(value) == 1
+	isRoman := validRoman && config.allowsScheme(roman)
+	isLetter := validLetter && config.allowsScheme(alpha)
+
+	if isRoman && isLetter {
+		previous, ok := previousSibling(stack, indent, kindOf(alpha))
+		if ok && len(previous) == 1 && strings.ToLower(previous)[0]+1 == lower[0] {
+			return kindOf(alpha), ""
+		}
+		return kindOf(roman), ""
+	}
+
+	switch {
+	case isLetter:
+		return kindOf(alpha), ""
+	case isRoman:
+		return kindOf(roman), ""
+	case validLetter:
+		return "", disabled(alpha)
+	case validRoman:
+		return "", disabled(roman)
+	}
+
+	return "", fmt.Sprintf("has an unrecognised marker %q", marker)
+}
+
+// previousSibling returns the marker of the last open item that a new item
+// of the given style would follow
+func previousSibling(stack []outlineEntry, indent int, kind markerKind) (string, bool) {
+	for i := len(stack) - 1; i >= 0; i-- {
+		entry := stack[i]
+		if entry.indent < indent {
+			break
+		}
+		if entry.indent == indent && entry.kind == kind {
+			return entry.value, true
+		}
+	}
+	return "", false
+}
+
+// indentationWidth measures leading whitespace, counting tabs as four columns
+func indentationWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+var describeHintRegex = regexp.MustCompile(`\(describe\s*\(\s*["']([^"']*)["']\s*\)\)`)
+
+// groupTitle returns the describe title for a group node. Titles written with
+// the legacy "(describe('Title'))" hint use the hinted title.
+func groupTitle(title string) string {
+	if matches := describeHintRegex.FindStringSubmatch(title); matches != nil {
+		return matches[1]
+	}
+	if before, _, found := strings.Cut(title, "(describe"); found {
+		return strings.TrimSpace(before)
+	}
+	return title
+}
+
+// renderAcNodes turns AC nodes into describe and it blocks
+func renderAcNodes(nodes []*acNode, settings specSettings) []tsNode {
+	var blocks []tsNode
+
+	for _, node := range nodes {
+		switch {
+		case node.Kind == acKindBackground:
+			blocks = append(blocks, acsSetupBlock(node))
+			continue
+		case node.Examples != nil && len(node.Examples.Rows) > 0:
+			blocks = append(blocks, acsEachBlock(node, settings))
+			continue
+		case !node.isGroup():
+			blocks = append(blocks, acsTestBlock(node, settings))
+			continue
+		}
+
+		var setup []tsNode
+		children := settings
+		if preconditions := acsPreconditions(node); len(preconditions) > 0 {
+			setup, children = acsMountBlock(preconditions, settings)
+		}
+
+		var body []tsNode
+		if node.ID != "" && settings.config.Acs.IDs == idsInComment {
+			body = append(body, tsComment(node.ID))
+		}
+		body = append(body, separated(append(setup, renderAcNodes(node.Children, children)...)...)...)
+		describe := tsTestBlock(acsCallee("describe", node.Skip, settings), acsDescribeTitle(node, settings), false, body...)
+		if len(node.Notes) > 0 {
+			describe = tsGroup{tsDocComment(node.Notes), describe}
+		}
+		blocks = append(blocks, describe)
+	}
+
+	return blocks
+}
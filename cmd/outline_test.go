@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// outlineShape renders a tree as "title(children...)" for compact comparisons
+func outlineShape(nodes []*acNode) string {
+	var parts []string
+	for _, node := range nodes {
+		part := node.Title
+		if node.isGroup() {
+			part += "(" + outlineShape(node.Children) + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestParseAcOutline(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "Unindented outline nests by numbering style",
+			text:     "1. Users\na. Create\nb. Delete\n2. Charts\na. Render",
+			expected: "Users(Create Delete) Charts(Render)",
+		},
+		{
+			name:     "Roman numerals nest under letters",
+			text:     "1. Dashboard\na. Loads\nb. Charts\ni. Render\nii. Refresh\nc. Export",
+			expected: "Dashboard(Loads Charts(Render Refresh) Export)",
+		},
+		{
+			name:     "Letters that look like roman numerals continue a list",
+			text:     "1. Steps\ng. Seven\nh. Eight\ni. Nine\nj. Ten",
+			expected: "Steps(Seven Eight Nine Ten)",
+		},
+		{
+			name:     "Single letter roman numerals",
+			text:     "1. Group\na. Item\ni. First\nii. Second\niii. Third\niv. Fourth\nv. Fifth",
+			expected: "Group(Item(First Second Third Fourth Fifth))",
+		},
+		{
+			name: "Indentation gives arbitrary depth",
+			text: `1. Level one
+    a. Level two
+        i. Level three
+            1. Level four
+                a. Level five
+        ii. Back to three
+    b. Back to two`,
+			expected: "Level one(Level two(Level three(Level four(Level five)) Back to three) Back to two)",
+		},
+		{
+			name:     "Indentation wins over numbering style",
+			text:     "1. Parent\n  2. Child\n3. Sibling",
+			expected: "Parent(Child) Sibling",
+		},
+		{
+			name:     "Tabs count as indentation",
+			text:     "1. Parent\n\ta. Child\n\t\ti. Grandchild",
+			expected: "Parent(Child(Grandchild))",
+		},
+		{
+			name:     "Lines without markers are ignored",
+			text:     "Some intro\n1. Only\nnote e.g. this\netc. that",
+			expected: "Only",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := outlineShape(parseAcOutline(tt.text, acsConfig{}).Nodes); result != tt.expected {
+				t.Errorf("parseAcOutline() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseAcOutlineSchemes(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		config   acsConfig
+		expected string
+	}{
+		{
+			name:     "Decimal outline",
+			text:     "1. Login\n1.1 Valid user\n1.2 Errors\n1.2.1 Wrong password\n1.2.2 Locked\n2. Logout",
+			expected: "Login(Valid user Errors(Wrong password Locked)) Logout",
+		},
+		{
+			name:     "Decimal outline with trailing dots",
+			text:     "1. Login\n1.1. Valid user\n1.2. Invalid user",
+			expected: "Login(Valid user Invalid user)",
+		},
+		{
+			name:     "Bullets",
+			text:     "- Cart\n  * Add item\n  * Remove item\n- Checkout\n  • Pay",
+			expected: "Cart(Add item Remove item) Checkout(Pay)",
+		},
+		{
+			name:     "Checkbox lists",
+			text:     "- Profile\n  - [ ] Edit name\n  - [x] Upload avatar\n  - Delete account",
+			expected: "Profile(Edit name Upload avatar Delete account)",
+		},
+		{
+			name:     "Mixed bullets are siblings",
+			text:     "- [x] Done\n* Star\n• Bullet\n  - Nested",
+			expected: "Done Star Bullet(Nested)",
+		},
+		{
+			name:     "Uppercase letters and roman numerals",
+			text:     "I. Admin\nA. Users\nB. Roles\nII. Reports",
+			expected: "Admin(Users Roles) Reports",
+		},
+		{
+			name:     "Parenthesised styles",
+			text:     "1) Search\n(a) By name\n(b) By date\n(i) Range\n2) Filters",
+			expected: "Search(By name By date(Range)) Filters",
+		},
+		{
+			name:     "Delimiters tell lists apart",
+			text:     "1. Outer\n1) Inner\n2) Inner two\n2. Outer two",
+			expected: "Outer(Inner Inner two) Outer two",
+		},
+		{
+			name:     "Disabled schemes are ignored",
+			text:     "1. Kept\n- Ignored bullet\na) Ignored delimiter\na. Kept letter",
+			config:   acsConfig{Markers: []string{schemeDecimal, schemeLowerAlpha}, Delimiters: []string{"."}},
+			expected: "Kept(Kept letter)",
+		},
+		{
+			name:     "Disabled checkboxes stay in the title",
+			text:     "- [ ] Todo",
+			config:   acsConfig{Markers: []string{schemeBullet}},
+			expected: "[ ] Todo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := outlineShape(parseAcOutline(tt.text, tt.config).Nodes); result != tt.expected {
+				t.Errorf("parseAcOutline() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseAcOutlineMetadata(t *testing.T) {
+	nodes := parseAcOutline("\n1. Parent\n  a. Child", acsConfig{}).Nodes
+
+	if len(nodes) != 1 || len(nodes[0].Children) != 1 {
+		t.Fatalf("parseAcOutline() returned unexpected tree %q", outlineShape(nodes))
+	}
+
+	child := nodes[0].Children[0]
+	if nodes[0].Line != 2 || child.Line != 3 {
+		t.Errorf("Expected lines 2 and 3, got %d and %d", nodes[0].Line, child.Line)
+	}
+	if nodes[0].Level != 1 || child.Level != 2 {
+		t.Errorf("Expected levels 1 and 2, got %d and %d", nodes[0].Level, child.Level)
+	}
+	if child.Marker != "a." {
+		t.Errorf("Expected marker a., got %q", child.Marker)
+	}
+}
+
+func TestParseAcOutlineDiagnostics(t *testing.T) {
+	text := "Intro\n1. Kept\n\na) Disabled delimiter\nab. Not a letter"
+	document := parseAcOutline(text, acsConfig{Delimiters: []string{"."}})
+
+	expected := []acDiagnostic{
+		{1, "Intro", "is not a list item"},
+		{4, "a) Disabled delimiter", `uses the ")" delimiter, which is disabled in ng-spec.json`},
+		{5, "ab. Not a letter", `has an unrecognised marker "ab."`},
+	}
+
+	if !reflect.DeepEqual(document.Diagnostics, expected) {
+		t.Errorf("parseAcOutline() diagnostics = %+v, want %+v", document.Diagnostics, expected)
+	}
+	if outlineShape(document.Nodes) != "Kept" {
+		t.Errorf("parseAcOutline() = %q, want %q", outlineShape(document.Nodes), "Kept")
+	}
+}
+
+func TestParseAcOutlineNotes(t *testing.T) {
+	text := "1. Login\nUsers sign in with SSO.\n  a. Valid credentials\n     The dashboard is shown.\n     - Unnumbered detail\n\n  b. Invalid credentials\n\nStray line"
+	document := parseAcOutline(text, acsConfig{Markers: []string{schemeDecimal, schemeLowerAlpha}})
+
+	login := document.Nodes[0]
+	if !reflect.DeepEqual(login.Notes, []string{"Users sign in with SSO."}) {
+		t.Errorf("Expected group notes, got %q", login.Notes)
+	}
+	if notes := login.Children[0].Notes; !reflect.DeepEqual(notes, []string{"The dashboard is shown.", "- Unnumbered detail"}) {
+		t.Errorf("Expected leaf notes, got %q", notes)
+	}
+	if len(document.Diagnostics) != 1 || document.Diagnostics[0].Line != 9 {
+		t.Errorf("Expected the unindented line after a blank line to be reported, got %+v", document.Diagnostics)
+	}
+
+	result := renderAcs(document.Nodes, defaultSpecSettings())
+	expectedPhrases := []string{
+		"\t/**\n\t * Users sign in with SSO.\n\t */\n\tdescribe('Login', () => {",
+		"\t\t\t// The dashboard is shown.\n\t\t\t// - Unnumbered detail\n\t\t\t// TODO: Implement test",
+	}
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("renderAcs() does not contain expected phrase %q in:\n%s", phrase, result)
+		}
+	}
+}
+
+func TestRenderAcsDescribeVersusIt(t *testing.T) {
+	text := "1. Standalone criterion\n2. Group (describe('Grouped'))\na. Nested criterion\nb. Leaf mentioning describe"
+	document := parseAcText("", text, acsConfig{})
+	result := renderAcs(document.Nodes, defaultSpecSettings().forAcs(document))
+
+	expectedPhrases := []string{
+		"it('should standalone criterion', async () => {",
+		"describe('Grouped', () => {",
+		"it('should nested criterion', async () => {",
+		"it('should leaf mentioning describe', async () => {",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("renderAcs() does not contain expected phrase %q in:\n%s", phrase, result)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Statuses of the tests in a traceability report with results
+const (
+	testPassed  = "passed"
+	testFailed  = "failed"
+	testSkipped = "skipped"
+	testTodo    = "todo"
+	testNotRun  = "not run"
+)
+
+// testResult is the outcome of a test in a test run report
+type testResult struct {
+	File string
+	// Names are the full names the test may have, JUnit reporters differ in
+	// how they split it between the suite and the test name
+	Names  []string
+	Status string
+}
+
+// jestReport is the part of a Jest or Vitest --json report that holds the
+// test results
+type jestReport struct {
+	TestResults []struct {
+		Name             string `json:"name"`
+		AssertionResults []struct {
+			FullName string `json:"fullName"`
+			Status   string `json:"status"`
+		} `json:"assertionResults"`
+	} `json:"testResults"`
+}
+
+// junitSuite is a JUnit XML testsuites or testsuite element
+type junitSuite struct {
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string         `xml:"name,attr"`
+	Classname string         `xml:"classname,attr"`
+	File      string         `xml:"file,attr"`
+	Failures  []junitElement `xml:"failure"`
+	Errors    []junitElement `xml:"error"`
+	Skipped   *junitElement  `xml:"skipped"`
+}
+
+type junitElement struct{}
+
+// parseTestResults reads a Jest or Vitest JSON report, or a JUnit XML report
+// such as the ones written by jest-junit and karma-junit-reporter
+func parseTestResults(data []byte) ([]testResult, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var report jestReport
+		if err := json.Unmarshal(trimmed, &report); err != nil {
+			return nil, fmt.Errorf("invalid Jest report: %w", err)
+		}
+
+		var results []testResult
+		for _, file := range report.TestResults {
+			for _, assertion := range file.AssertionResults {
+				results = append(results, testResult{
+					File:   file.Name,
+					Names:  []string{assertion.FullName},
+					Status: jestStatus(assertion.Status),
+				})
+			}
+		}
+		return results, nil
+
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		var root junitSuite
+		if err := xml.Unmarshal(trimmed, &root); err != nil {
+			return nil, fmt.Errorf("invalid JUnit report: %w", err)
+		}
+		return junitResults(root), nil
+	}
+
+	return nil, fmt.Errorf("unrecognised test report, use a Jest JSON or JUnit XML report")
+}
+
+func jestStatus(status string) string {
+	switch status {
+	case "passed", "failed", "todo":
+		return status
+	}
+	// pending, skipped and disabled
+	return testSkipped
+}
+
+func junitResults(suite junitSuite) []testResult {
+	var results []testResult
+	for _, testCase := range suite.Cases {
+		result := testResult{File: testCase.File, Status: testPassed}
+		switch {
+		case len(testCase.Failures) > 0 || len(testCase.Errors) > 0:
+			result.Status = testFailed
+		case testCase.Skipped != nil:
+			result.Status = testSkipped
+		}
+
+		result.Names = []string{testCase.Name}
+		if testCase.Classname != "" {
+			result.Names = append(result.Names, testCase.Classname+" "+testCase.Name)
+			// Karma prefixes the class name with the browser
+			if _, suites, found := strings.Cut(testCase.Classname, "."); found {
+				result.Names = append(result.Names, suites+" "+testCase.Name)
+			}
+		}
+		results = append(results, result)
+	}
+
+	for _, child := range suite.Suites {
+		results = append(results, junitResults(child)...)
+	}
+	return results
+}
+
+// applyTestResults sets the status of the traced tests and counts them per
+// ticket. Stubs count as TODO even when they pass, and tests missing from
+// the report as not run.
+func applyTestResults(tickets []*traceTicket, results []testResult) {
+	byName := map[string][]testResult{}
+	for _, result := range results {
+		for _, name := range result.Names {
+			byName[name] = append(byName[name], result)
+		}
+	}
+
+	for _, ticket := range tickets {
+		ticket.Todo = 0
+		for i := range ticket.Tests {
+			test := &ticket.Tests[i]
+			test.Status = testNotRun
+			if matches := findTestResults(test, byName, results); len(matches) > 0 {
+				test.Status = combinedStatus(matches)
+			}
+			if test.Todo {
+				test.Status = testTodo
+			}
+
+			switch test.Status {
+			case testPassed:
+				ticket.Passed++
+			case testFailed:
+				ticket.Failed++
+			case testSkipped:
+				ticket.Skipped++
+			case testTodo:
+				ticket.Todo++
+			default:
+				ticket.NotRun++
+			}
+		}
+	}
+}
+
+// findTestResults returns the results of a test, those from its file when
+// the report says. Tests with <placeholders> or it.each formats such as %s
+// in their name run once per example, so they match every result their name
+// fits.
+func findTestResults(test *traceTest, byName map[string][]testResult, results []testResult) []testResult {
+	candidates := byName[test.Name]
+	if len(candidates) == 0 && (examplePlaceholderRegex.MatchString(test.Name) || eachFormatRegex.MatchString(test.Name)) {
+		name := examplePlaceholderRegex.ReplaceAllLiteralString(regexp.QuoteMeta(test.Name), ".+")
+		name = strings.ReplaceAll(eachFormatRegex.ReplaceAllLiteralString(name, ".+"), "%%", "%")
+		pattern := regexp.MustCompile("^" + name + "$")
+		for _, result := range results {
+			if slices.ContainsFunc(result.Names, pattern.MatchString) {
+				candidates = append(candidates, result)
+			}
+		}
+	}
+
+	var inFile []testResult
+	for _, result := range candidates {
+		if result.File != "" && strings.HasSuffix(filepath.ToSlash(result.File), test.File) {
+			inFile = append(inFile, result)
+		}
+	}
+	if len(inFile) > 0 {
+		return inFile
+	}
+	return candidates
+}
+
+// combinedStatus returns the status of a test that ran several times: failed
+// when any run failed and passed when any other passed
+func combinedStatus(results []testResult) string {
+	status := results[0].Status
+	for _, result := range results {
+		switch {
+		case result.Status == testFailed:
+			return testFailed
+		case result.Status == testPassed:
+			status = testPassed
+		}
+	}
+	return status
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTestResults(t *testing.T) {
+	tests := []struct {
+		name     string
+		report   string
+		expected []testResult
+	}{
+		{
+			name: "Jest JSON",
+			report: `{"testResults": [{"name": "/repo/src/a.spec.ts", "assertionResults": [
+				{"fullName": "A should pass", "status": "passed"},
+				{"fullName": "A should fail", "status": "failed"},
+				{"fullName": "A should wait", "status": "pending"}
+			]}]}`,
+			expected: []testResult{
+				{File: "/repo/src/a.spec.ts", Names: []string{"A should pass"}, Status: testPassed},
+				{File: "/repo/src/a.spec.ts", Names: []string{"A should fail"}, Status: testFailed},
+				{File: "/repo/src/a.spec.ts", Names: []string{"A should wait"}, Status: testSkipped},
+			},
+		},
+		{
+			name: "jest-junit",
+			report: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="jest tests">
+  <testsuite name="A">
+    <testcase classname="A should pass" name="A should pass" time="0.01"></testcase>
+    <testcase classname="A should fail" name="A should fail" time="0.01"><failure>Error</failure></testcase>
+  </testsuite>
+</testsuites>`,
+			expected: []testResult{
+				{Names: []string{"A should pass", "A should pass A should pass"}, Status: testPassed},
+				{Names: []string{"A should fail", "A should fail A should fail"}, Status: testFailed},
+			},
+		},
+		{
+			name: "karma-junit-reporter",
+			report: `<testsuite name="Chrome 120.0.0.0 (Linux x86_64)">
+  <testcase name="should skip" classname="Chrome_120_0_0_0_(Linux_x86_64).A B"><skipped/></testcase>
+</testsuite>`,
+			expected: []testResult{
+				{Names: []string{"should skip", "Chrome_120_0_0_0_(Linux_x86_64).A B should skip", "A B should skip"}, Status: testSkipped},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := parseTestResults([]byte(tt.report))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(results, tt.expected) {
+				t.Errorf("parseTestResults() = %+v, want %+v", results, tt.expected)
+			}
+		})
+	}
+
+	if _, err := parseTestResults([]byte("TAP version 13")); err == nil {
+		t.Error("parseTestResults() should reject unknown reports")
+	}
+}
+
+func TestApplyTestResults(t *testing.T) {
+	ticket := &traceTicket{Tests: []traceTest{
+		{Name: "A should pass", File: "src/a.spec.ts"},
+		{Name: "A should fail", File: "src/a.spec.ts"},
+		{Name: "A should be a stub", File: "src/a.spec.ts", Todo: true},
+		{Name: "A should log in as <role>", File: "src/a.spec.ts"},
+		{Name: "A should never run", File: "src/a.spec.ts"},
+		{Name: "A should total 100%% (quantity: %s, total: %s)", File: "src/a.spec.ts"},
+	}}
+	results := []testResult{
+		{File: "/repo/src/b.spec.ts", Names: []string{"A should pass"}, Status: testFailed},
+		{File: "/repo/src/a.spec.ts", Names: []string{"A should pass"}, Status: testPassed},
+		{Names: []string{"A should fail"}, Status: testFailed},
+		{Names: []string{"A should be a stub"}, Status: testPassed},
+		{Names: []string{"A should log in as admin"}, Status: testPassed},
+		{Names: []string{"A should log in as guest"}, Status: testFailed},
+		{Names: []string{"A should total 100% (quantity: 1, total: 10)"}, Status: testPassed},
+		{Names: []string{"A should total 100% (quantity: 2, total: 18)"}, Status: testFailed},
+	}
+
+	applyTestResults([]*traceTicket{ticket}, results)
+
+	var statuses []string
+	for _, test := range ticket.Tests {
+		statuses = append(statuses, test.Status)
+	}
+	expected := []string{testPassed, testFailed, testTodo, testFailed, testNotRun, testFailed}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("applyTestResults() statuses = %q, want %q", statuses, expected)
+	}
+
+	if ticket.Passed != 1 || ticket.Failed != 3 || ticket.Todo != 1 || ticket.NotRun != 1 {
+		t.Errorf("Unexpected counts %+v", ticket)
+	}
+}
+
+func TestTraceCommandWithResults(t *testing.T) {
+	root := writeTraceFiles(t, map[string]string{"dashboard.component.spec.ts": traceSpec})
+	report := filepath.Join(t.TempDir(), "results.json")
+	content := `{"testResults": [{"name": "/repo/dashboard.component.spec.ts", "assertionResults": [
+		{"fullName": "DashboardComponent Widgets [C1] should show the revenue", "status": "failed"}
+	]}]}`
+	if err := os.WriteFile(report, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := traceCommand(&out, []string{root}, traceMarkdown, report); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "| Ticket | Criteria | Passed | Failed | TODO | Skipped | Not run | Files |\n" +
+		"| --- | ---: | ---: | ---: | ---: | ---: | ---: | --- |\n" +
+		"| https://example.atlassian.net/browse/DASH-12 | 3 | 0 | 1 | 2 | 0 | 0 | `dashboard.component.spec.ts` |\n"
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("traceCommand() = %q, want %q", out.String(), expected)
+	}
+}
@@ -36,7 +36,12 @@ var rootCmd = &cobra.Command{
 	ng-spec
 	ng-spec app
 	ng-spec /path/to/component
+	ng-spec user --acs-file acs.txt --strict
+	ng-spec user --ticket PROJ-123
+	ng-spec user --ticket PROJ-124 --merge
 	`,
+	// Without Args, cobra treats the component name as an unknown subcommand
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		var component string
 
@@ -49,7 +54,10 @@ var rootCmd = &cobra.Command{
 			component = args[0]
 		}
 
-		generateComponentTest(component)
+		if err := generateComponentTest(component, options); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
 	},
 	Version: version,
 }
@@ -81,6 +89,16 @@ func init() {
 	}
 }
 
+var options generateOptions
+
+func init() {
+	rootCmd.Flags().StringVar(&options.acsFile, "acs-file", "", "read the ACs from a file instead of the form, - reads stdin")
+	rootCmd.Flags().StringVar(&options.ticket, "ticket", "", "fetch the ACs of a ticket from the issue tracker configured in ng-spec.json")
+	rootCmd.Flags().StringVar(&options.acsLink, "acs-link", "", "link to the ACs ticket")
+	rootCmd.Flags().BoolVar(&options.strict, "strict", false, "fail when AC lines can't be parsed")
+	rootCmd.Flags().BoolVar(&options.merge, "merge", false, "add the tests for new ACs to an existing spec, keeping the tests it has")
+}
+
 func init() {
 	versionTemplate := logo + `
   Version                      %s
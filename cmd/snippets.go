@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// snippetsConfig maps the verbs of AC titles to Testing Library calls
+// written in the generated tests
+type snippetsConfig struct {
+	// Rules are tried before the built-in ones
+	Rules []snippetRule `json:"rules"`
+	// DisableDefaults turns the built-in rules off
+	DisableDefaults bool `json:"disableDefaults"`
+}
+
+// snippetRule writes its snippet in the tests of criteria starting with one
+// of its verbs. {target} is replaced by the rest of the clause as a string
+// and {pattern} as a case-insensitive regular expression. Single-quoted
+// strings are written with the quotes of the format.
+type snippetRule struct {
+	Verbs   []string `json:"verbs"`
+	Snippet string   `json:"snippet"`
+}
+
+// Built-in rules, the longer verbs first where they share a prefix
+var defaultSnippetRules = []snippetRule{
+	{
+		Verbs:   []string{"does not see", "doesn't see", "cannot see", "can't see", "no longer sees"},
+		Snippet: "expect(screen.queryByText({pattern})).not.toBeInTheDocument();",
+	},
+	{
+		Verbs:   []string{"clicks on", "click on", "clicks", "click", "presses", "press", "taps", "tap", "submits", "submit"},
+		Snippet: "await userEvent.click(screen.getByRole('button', { name: {pattern} }));",
+	},
+	{
+		Verbs:   []string{"types", "type", "enters", "enter", "fills in", "fill in"},
+		Snippet: "await userEvent.type(screen.getByLabelText({pattern}), 'TODO');",
+	},
+	{
+		Verbs:   []string{"selects", "select", "chooses", "choose", "picks", "pick"},
+		Snippet: "await userEvent.selectOptions(screen.getByLabelText({pattern}), 'TODO');",
+	},
+	{
+		Verbs:   []string{"sees", "see", "shows", "show", "displays", "display"},
+		Snippet: "expect(screen.getByText({pattern})).toBeVisible();",
+	},
+}
+
+// The built-in assertions use jest-dom matchers, which Jasmine specs only
+// have with @testing-library/jasmine-dom, so Jasmine gets plain matchers
+var jasmineMatchers = strings.NewReplacer(
+	".not.toBeInTheDocument()", ".toBeNull()",
+	".toBeVisible()", ".toBeTruthy()",
+)
+
+var (
+	// Subjects and modals before the verb of a criterion
+	snippetSubjectRegex = regexp.MustCompile(`(?i)^(?:should\s+)?(?:(?:the\s+)?(?:user|customer|visitor|admin|i)\s+)?(?:(?:can|should|must|will|is able to)\s+)?`)
+	// Clauses of a criterion such as "types an email and clicks Save"
+	snippetClauseRegex = regexp.MustCompile(`(?i),?\s+(?:and\s+then|and|then)\s+|,\s+`)
+	// Articles before the target and the kind of element after it
+	snippetArticleRegex = regexp.MustCompile(`(?i)^(?:the|a|an|on|in|into)\s+`)
+	snippetElementRegex = regexp.MustCompile(`(?i)\s+(?:button|link|field|input|box|dropdown)$`)
+	// Single-quoted strings in a snippet
+	snippetStringRegex = regexp.MustCompile(`'(?:[^'\\\n]|\\.)*'`)
+	// Quoted text in a clause, as in `shows the "Saved" message`
+	snippetQuotedRegex = regexp.MustCompile("\"([^\"]+)\"|“([^”]+)”|‘([^’]+)’|`([^`]+)`|(?:^|\\s)'([^']+)'(?:\\s|$)")
+)
+
+// usesUserEvent reports whether snippets may call user-event
+func (c snippetsConfig) usesUserEvent() bool {
+	return slices.ContainsFunc(c.snippetRules(frameworkUnknown), func(rule snippetRule) bool {
+		return strings.Contains(rule.Snippet, "userEvent.")
+	})
+}
+
+// snippetRules returns the rules in the order they're tried, the built-in
+// ones with the matchers of the framework
+func (c snippetsConfig) snippetRules(framework testFramework) []snippetRule {
+	if c.DisableDefaults {
+		return c.Rules
+	}
+
+	defaults := defaultSnippetRules
+	if framework.usesJasmine() {
+		defaults = nil
+		for _, rule := range defaultSnippetRules {
+			defaults = append(defaults, snippetRule{rule.Verbs, jasmineMatchers.Replace(rule.Snippet)})
+		}
+	}
+	return slices.Concat(c.Rules, defaults)
+}
+
+// acsSnippets returns the snippets for the clauses of a criterion's title
+// that start with the verb of a rule, in the order of the clauses. Clauses
+// acting on an example placeholder such as <name> are left out, as the
+// placeholder isn't text the page shows.
+func acsSnippets(title string, config snippetsConfig, framework testFramework) []tsNode {
+	rules := config.snippetRules(framework)
+
+	var snippets []tsNode
+	for _, clause := range snippetClauseRegex.Split(strings.TrimRight(title, ".!"), -1) {
+		clause = strings.TrimSpace(snippetSubjectRegex.ReplaceAllString(strings.TrimSpace(clause), ""))
+		lower := strings.ToLower(clause)
+
+		for _, rule := range rules {
+			i := slices.IndexFunc(rule.Verbs, func(verb string) bool {
+				return strings.HasPrefix(lower, strings.ToLower(verb)+" ")
+			})
+			if i < 0 {
+				continue
+			}
+
+			target := snippetTarget(clause[len(rule.Verbs[i]):])
+			if target == "" || examplePlaceholderRegex.MatchString(target) {
+				break
+			}
+			for _, line := range strings.Split(strings.TrimSpace(rule.Snippet), "\n") {
+				snippets = append(snippets, tsExprStatement{tsSnippet{strings.TrimSuffix(strings.TrimSpace(line), ";"), target}})
+			}
+			break
+		}
+	}
+	return snippets
+}
+
+// snippetTarget returns the element a clause acts on: its quoted text if it
+// has some, otherwise the clause without articles and the kind of element
+func snippetTarget(text string) string {
+	if matches := snippetQuotedRegex.FindStringSubmatch(text); matches != nil {
+		for _, quoted := range matches[1:] {
+			if quoted != "" {
+				return strings.TrimSpace(quoted)
+			}
+		}
+	}
+
+	target := strings.TrimSpace(text)
+	target = snippetArticleRegex.ReplaceAllString(target, "")
+	target = snippetElementRegex.ReplaceAllString(target, "")
+	return strings.Trim(target, "\"'`“”‘’ ")
+}
+
+// tsSnippet is a snippet with its placeholders replaced by the target,
+// quoted for the format in use
+type tsSnippet struct {
+	code   string
+	target string
+}
+
+func (e tsSnippet) printExpr(p *tsPrinter, level, used int) string {
+	code := snippetStringRegex.ReplaceAllStringFunc(e.code, func(literal string) string {
+		return p.format.stringLiteral(decodeTsString(literal))
+	})
+
+	pattern := regexp.QuoteMeta(e.target)
+	pattern = strings.ReplaceAll(pattern, "/", `\/`)
+	return strings.NewReplacer(
+		"{target}", p.format.stringLiteral(e.target),
+		"{pattern}", "/"+pattern+"/i",
+	).Replace(code)
+}
+
+// Identifiers used by snippets and the imports they need
+var snippetImports = []specImport{
+	{"screen", testingLibraryPackage, false, `\bscreen\.`},
+	{"userEvent", userEventPackage, true, `\buserEvent\.`},
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import "testing"
+
+func TestAcsSnippets(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		config    snippetsConfig
+		framework testFramework
+		expected  string
+	}{
+		{
+			name:     "Click",
+			title:    `User clicks the "Save" button`,
+			expected: "\tawait userEvent.click(screen.getByRole('button', { name: /Save/i }));\n",
+		},
+		{
+			name:  "Clauses",
+			title: "Types an email and then clicks Sign in",
+			expected: "\tawait userEvent.type(screen.getByLabelText(/email/i), 'TODO');\n" +
+				"\tawait userEvent.click(screen.getByRole('button', { name: /Sign in/i }));\n",
+		},
+		{
+			name:     "Quoted text",
+			title:    `Should show "saved" message.`,
+			expected: "\texpect(screen.getByText(/saved/i)).toBeVisible();\n",
+		},
+		{
+			name:     "Select",
+			title:    "The user can select a country",
+			expected: "\tawait userEvent.selectOptions(screen.getByLabelText(/country/i), 'TODO');\n",
+		},
+		{
+			name:     "Sees",
+			title:    "Sees an error message (required).",
+			expected: "\texpect(screen.getByText(/error message \\(required\\)/i)).toBeVisible();\n",
+		},
+		{
+			name:     "Does not see",
+			title:    "Does not see the banner",
+			expected: "\texpect(screen.queryByText(/banner/i)).not.toBeInTheDocument();\n",
+		},
+		{
+			name:      "Jasmine sees",
+			title:     "Sees an error message",
+			framework: frameworkKarma,
+			expected:  "\texpect(screen.getByText(/error message/i)).toBeTruthy();\n",
+		},
+		{
+			name:      "Jasmine does not see",
+			title:     "Does not see the banner",
+			framework: frameworkKarma,
+			expected:  "\texpect(screen.queryByText(/banner/i)).toBeNull();\n",
+		},
+		{
+			name:     "Placeholder",
+			title:    "Shows the <name> greeting and clicks Save",
+			expected: "\tawait userEvent.click(screen.getByRole('button', { name: /Save/i }));\n",
+		},
+		{
+			name:     "No verb",
+			title:    "Saves the draft",
+			expected: "",
+		},
+		{
+			name:  "Custom rule first",
+			title: "Clicks Save",
+			config: snippetsConfig{Rules: []snippetRule{
+				{Verbs: []string{"clicks"}, Snippet: "await user.click(screen.getByText({target}));\nexpect(true).toBe(true)"},
+			}},
+			expected: "\tawait user.click(screen.getByText('Save'));\n\texpect(true).toBe(true);\n",
+		},
+		{
+			name:     "Defaults disabled",
+			title:    "Clicks Save",
+			config:   snippetsConfig{DisableDefaults: true},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := printNodes(defaultFormatOptions(), 1, acsSnippets(tt.title, tt.config, tt.framework))
+			if result != tt.expected {
+				t.Errorf("acsSnippets() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAcsSnippetsQuotes(t *testing.T) {
+	format := defaultFormatOptions()
+	format.singleQuote = false
+
+	expected := "\tawait userEvent.type(screen.getByLabelText(/email/i), \"TODO\");\n" +
+		"\tawait userEvent.click(screen.getByRole(\"button\", { name: /Save/i }));\n"
+	if result := printNodes(format, 1, acsSnippets("Types an email and clicks Save", snippetsConfig{}, frameworkJest)); result != expected {
+		t.Errorf("acsSnippets() = %q, want %q", result, expected)
+	}
+}
+
+func TestRenderAcsSnippetsScan(t *testing.T) {
+	acs := "1. Shows the \"don't\" (quoted) label\n2. Clicks Save/Close\n3. Saves the draft"
+	document := parseAcText("", acs, acsConfig{})
+	source := renderAcs(document.Nodes, defaultSpecSettings().forAcs(document))
+
+	expected := "should show the \"don't\" (quoted) label* should click Save/Close* should save the draft*"
+	if result := specShape(parseSpecBlocks(source)); result != expected {
+		t.Errorf("parseSpecBlocks() found %q, want %q in:\n%s", result, expected, source)
+	}
+}
@@ -0,0 +1,431 @@
+package cmd
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	specCallRegex        = regexp.MustCompile(`^[xf]?(describe|it|test)((?:\.(?:skip|only|todo|each|concurrent))*)(?:<[^()]*>)?\s*\(`)
+	specEachParamRegex   = regexp.MustCompile(`\$([A-Za-z_$][\w$]*)`)
+	specTemplateArgRegex = regexp.MustCompile(`\$\{\s*([^{}]*?)\s*\}`)
+	specHashRegex        = regexp.MustCompile(`^\s*;?\s*//\s*ac:([0-9a-f]+)\b`)
+)
+
+// specTodo is the comment left in generated tests until they're implemented
+const specTodo = "TODO: Implement test"
+
+// specBlock is a describe or it block read from an existing spec file
+type specBlock struct {
+	// Callee is the function called, e.g. describe, it.skip or xit
+	Callee string
+	Title  string
+	Line   int
+	// Start and End are the offsets of the call and of the end of its closing
+	// parenthesis
+	Start, End int
+	// BodyStart and BodyEnd are the offsets of the braces of the callback
+	// body, -1 when the callback isn't written inline
+	BodyStart, BodyEnd int
+	// Todo is set for tests that are still the generated stub
+	Todo bool
+	// Hash is the hash of the AC a test was generated from, read from the
+	// comment after it
+	Hash string
+	// Examples are the rows of an it.each table written as literals
+	Examples []specRow
+	Children []*specBlock
+}
+
+// specRow is a row of an it.each table. The values of object rows are keyed
+// by their property, those of array rows only have a position.
+type specRow []specValue
+
+type specValue struct {
+	key, value string
+}
+
+func (b *specBlock) isDescribe() bool {
+	return strings.Contains(b.Callee, "describe")
+}
+
+func (b *specBlock) isSkipped() bool {
+	return strings.HasPrefix(b.Callee, "x") || strings.Contains(b.Callee, ".skip")
+}
+
+// parseSpecBlocks reads the describe and it tree of a spec file. It is a
+// scanner rather than a TypeScript parser: calls are found anywhere outside
+// strings and comments, so tests inside loops and helpers are included.
+func parseSpecBlocks(source string) []*specBlock {
+	return scanSpecBlocks(source, 0, len(source))
+}
+
+func scanSpecBlocks(source string, start, end int) []*specBlock {
+	var blocks []*specBlock
+
+	for i := start; i < end; {
+		c := source[i]
+		switch {
+		case strings.HasPrefix(source[i:end], "//"), strings.HasPrefix(source[i:end], "/*"):
+			i = skipTsComment(source, i)
+		case c == '\'' || c == '"' || c == '`':
+			i = skipTsString(source, i)
+		case c == '/' && isTsRegexStart(source, i):
+			i = skipTsRegex(source, i)
+		case isTsIdentifierPart(c):
+			if i == start || (!isTsIdentifierPart(source[i-1]) && source[i-1] != '.') {
+				if block := readSpecCall(source, i, end); block != nil {
+					blocks = append(blocks, block)
+					i = block.End
+					continue
+				}
+			}
+			for i < end && isTsIdentifierPart(source[i]) {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+
+	return blocks
+}
+
+// readSpecCall reads the describe or it call starting at offset i, or returns
+// nil when there's none
+func readSpecCall(source string, i, end int) *specBlock {
+	matches := specCallRegex.FindStringSubmatchIndex(source[i:end])
+	if matches == nil {
+		return nil
+	}
+
+	block := &specBlock{
+		// Type arguments, as in it.each<[number, string]>, aren't part of it
+		Callee:    source[i : i+matches[5]],
+		Line:      strings.Count(source[:i], "\n") + 1,
+		Start:     i,
+		BodyStart: -1,
+		BodyEnd:   -1,
+	}
+
+	open := i + matches[1] - 1
+	each := strings.Contains(block.Callee, ".each")
+	if each {
+		// it.each(table)(title, fn)
+		tableEnd := matchTsBracket(source, open)
+		block.Examples = readSpecTable(source, open+1, tableEnd)
+		open = skipTsSpace(source, tableEnd+1)
+		if open >= end || source[open] != '(' {
+			return nil
+		}
+	}
+
+	close := matchTsBracket(source, open)
+	if close >= end {
+		return nil
+	}
+	block.End = close + 1
+
+	lineEnd := strings.IndexByte(source[block.End:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(source) - block.End
+	}
+	if matches := specHashRegex.FindStringSubmatch(source[block.End : block.End+lineEnd]); matches != nil {
+		block.Hash = matches[1]
+	}
+
+	titleStart := skipTsSpace(source, open+1)
+	titleEnd := titleStart
+	if titleStart < close && strings.ContainsRune("'\"`", rune(source[titleStart])) {
+		titleEnd = skipTsString(source, titleStart)
+		block.Title = decodeTsString(source[titleStart:titleEnd])
+	} else {
+		titleEnd = skipTsArgument(source, titleStart, close)
+		block.Title = strings.TrimSpace(source[titleStart:titleEnd])
+	}
+	if each {
+		block.Title = specEachParamRegex.ReplaceAllString(block.Title, "<$1>")
+	}
+
+	block.BodyStart, block.BodyEnd = findTsCallbackBody(source, titleEnd, close)
+	if block.BodyStart < 0 {
+		block.Todo = strings.Contains(block.Callee, ".todo")
+		return block
+	}
+
+	if block.isDescribe() {
+		block.Children = scanSpecBlocks(source, block.BodyStart+1, block.BodyEnd)
+	} else {
+		block.Todo = strings.Contains(source[block.BodyStart:block.BodyEnd], specTodo)
+	}
+	return block
+}
+
+// readSpecTable reads the rows of the it.each table between start and end.
+// Rows and values that aren't literals are left out.
+func readSpecTable(source string, start, end int) []specRow {
+	open := skipTsSpace(source, start)
+	if open >= end || source[open] != '[' {
+		return nil
+	}
+
+	var rows []specRow
+	for _, element := range splitTsList(source, open+1, matchTsBracket(source, open)) {
+		var row specRow
+		switch element[0] {
+		case '[':
+			for _, value := range splitTsList(element, 1, len(element)-1) {
+				row = append(row, specValue{value: tsLiteralValue(value)})
+			}
+		case '{':
+			for _, property := range splitTsList(element, 1, len(element)-1) {
+				key, value, ok := strings.Cut(property, ":")
+				key = strings.TrimSpace(key)
+				if !ok || key == "" {
+					continue
+				}
+				if strings.ContainsRune("'\"", rune(key[0])) {
+					key = decodeTsString(key)
+				}
+				row = append(row, specValue{key, tsLiteralValue(strings.TrimSpace(value))})
+			}
+		default:
+			row = specRow{{value: tsLiteralValue(element)}}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// splitTsList returns the comma-separated items between start and end,
+// trimmed and without the empty item a trailing comma leaves
+func splitTsList(source string, start, end int) []string {
+	var items []string
+	for i := start; i < end; {
+		itemEnd := skipTsArgument(source, i, end)
+		if item := strings.TrimSpace(source[i:itemEnd]); item != "" {
+			items = append(items, item)
+		}
+		i = itemEnd + 1
+	}
+	return items
+}
+
+// tsLiteralValue returns the value of a string literal, or the code of any
+// other value such as a number
+func tsLiteralValue(code string) string {
+	if code != "" && strings.ContainsRune("'\"`", rune(code[0])) {
+		return decodeTsString(code)
+	}
+	return code
+}
+
+// findTsCallbackBody returns the offsets of the braces around the body of
+// the first arrow function or function expression between start and end
+func findTsCallbackBody(source string, start, end int) (int, int) {
+	for i := start; i < end; {
+		switch {
+		case strings.HasPrefix(source[i:end], "//"), strings.HasPrefix(source[i:end], "/*"):
+			i = skipTsComment(source, i)
+		case source[i] == '\'' || source[i] == '"' || source[i] == '`':
+			i = skipTsString(source, i)
+		case source[i] == '/' && isTsRegexStart(source, i):
+			i = skipTsRegex(source, i)
+		case strings.HasPrefix(source[i:end], "=>"):
+			open := skipTsSpace(source, i+2)
+			if open < end && source[open] == '{' {
+				return open, matchTsBracket(source, open)
+			}
+			return -1, -1
+		case strings.HasPrefix(source[i:end], "function") && (i == 0 || !isTsIdentifierPart(source[i-1])):
+			params := strings.IndexByte(source[i:end], '(')
+			if params < 0 {
+				return -1, -1
+			}
+			open := skipTsSpace(source, matchTsBracket(source, i+params)+1)
+			if open < end && source[open] == '{' {
+				return open, matchTsBracket(source, open)
+			}
+			return -1, -1
+		default:
+			i++
+		}
+	}
+	return -1, -1
+}
+
+// matchTsBracket returns the offset of the bracket closing the one at open,
+// or len(source) when it isn't closed
+func matchTsBracket(source string, open int) int {
+	depth := 0
+	for i := open; i < len(source); {
+		switch c := source[i]; {
+		case strings.HasPrefix(source[i:], "//"), strings.HasPrefix(source[i:], "/*"):
+			i = skipTsComment(source, i)
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			i = skipTsString(source, i)
+			continue
+		case c == '/' && isTsRegexStart(source, i):
+			i = skipTsRegex(source, i)
+			continue
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+		i++
+	}
+	return len(source)
+}
+
+// skipTsArgument returns the offset of the comma ending the argument that
+// starts at start, or end
+func skipTsArgument(source string, start, end int) int {
+	for i := start; i < end; {
+		switch c := source[i]; {
+		case c == ',':
+			return i
+		case c == '(' || c == '[' || c == '{':
+			i = matchTsBracket(source, i) + 1
+		case c == '\'' || c == '"' || c == '`':
+			i = skipTsString(source, i)
+		case c == '/' && isTsRegexStart(source, i):
+			i = skipTsRegex(source, i)
+		default:
+			i++
+		}
+	}
+	return end
+}
+
+// skipTsString returns the offset after the string or template literal that
+// starts at i
+func skipTsString(source string, i int) int {
+	quote := source[i]
+	for i++; i < len(source); i++ {
+		switch {
+		case source[i] == '\\':
+			i++
+		case source[i] == quote:
+			return i + 1
+		case quote == '`' && strings.HasPrefix(source[i:], "${"):
+			i = matchTsBracket(source, i+1)
+		case quote != '`' && source[i] == '\n':
+			return i
+		}
+	}
+	return len(source)
+}
+
+// isTsRegexStart reports whether the slash at i starts a regular expression
+// literal rather than a division or a comment, from the token before it
+func isTsRegexStart(source string, i int) bool {
+	if strings.HasPrefix(source[i:], "//") || strings.HasPrefix(source[i:], "/*") {
+		return false
+	}
+	before := strings.TrimRight(source[:i], " \t\r\n")
+	if before == "" {
+		return true
+	}
+	if c := before[len(before)-1]; !isTsIdentifierPart(c) {
+		return !strings.ContainsRune(")]}'\"`", rune(c))
+	}
+	start := len(before)
+	for start > 0 && isTsIdentifierPart(before[start-1]) {
+		start--
+	}
+	return slices.Contains([]string{"return", "typeof", "case", "in", "of", "void", "delete", "throw", "await", "yield"}, before[start:])
+}
+
+// skipTsRegex returns the offset after the regular expression literal and
+// its flags that start at i
+func skipTsRegex(source string, i int) int {
+	class := false
+	for i++; i < len(source); i++ {
+		switch c := source[i]; {
+		case c == '\\':
+			i++
+		case c == '\n':
+			return i
+		case c == '[':
+			class = true
+		case c == ']':
+			class = false
+		case c == '/' && !class:
+			for i++; i < len(source) && isTsIdentifierPart(source[i]); i++ {
+			}
+			return i
+		}
+	}
+	return len(source)
+}
+
+// skipTsComment returns the offset after the comment that starts at i
+func skipTsComment(source string, i int) int {
+	if strings.HasPrefix(source[i:], "//") {
+		if end := strings.IndexByte(source[i:], '\n'); end >= 0 {
+			return i + end
+		}
+		return len(source)
+	}
+	if end := strings.Index(source[i+2:], "*/"); end >= 0 {
+		return i + 2 + end + 2
+	}
+	return len(source)
+}
+
+func skipTsSpace(source string, i int) int {
+	for i < len(source) && strings.ContainsRune(" \t\r\n", rune(source[i])) {
+		i++
+	}
+	return i
+}
+
+func isTsIdentifierPart(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// decodeTsString returns the value of a string literal. Expressions in
+// template literals are written as <placeholders>, the way ACs name the
+// values of examples.
+func decodeTsString(literal string) string {
+	quote := literal[0]
+	body := strings.TrimSuffix(literal[1:], string(quote))
+	if quote == '`' {
+		body = specTemplateArgRegex.ReplaceAllString(body, "<$1>")
+	}
+
+	var result strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] != '\\' || i+1 == len(body) {
+			result.WriteByte(body[i])
+			continue
+		}
+
+		i++
+		switch body[i] {
+		case 'n':
+			result.WriteByte('\n')
+		case 't':
+			result.WriteByte('\t')
+		case 'u':
+			if i+4 < len(body) {
+				if code, err := strconv.ParseUint(body[i+1:i+5], 16, 32); err == nil {
+					result.WriteRune(rune(code))
+					i += 4
+					continue
+				}
+			}
+			result.WriteByte('u')
+		default:
+			result.WriteByte(body[i])
+		}
+	}
+	return result.String()
+}
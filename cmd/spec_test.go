@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// specShape summarises a spec block tree as "Title(children)" with stubs
+// marked by a trailing *
+func specShape(blocks []*specBlock) string {
+	var parts []string
+	for _, block := range blocks {
+		part := block.Title
+		if block.Todo {
+			part += "*"
+		}
+		if len(block.Children) > 0 {
+			part += "(" + specShape(block.Children) + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestParseSpecBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name: "Nested blocks",
+			source: `describe('Dashboard', () => {
+	it('should render', async () => {
+		expect(true).toBe(true);
+	});
+
+	describe("Widgets", function () {
+		it('should show the revenue', async () => {
+			// TODO: Implement test
+		});
+	});
+});`,
+			expected: "Dashboard(should render Widgets(should show the revenue*))",
+		},
+		{
+			name: "Strings and comments",
+			source: `describe('Dashboard', () => {
+	// it('commented out', () => {})
+	const title = "it('in a string', () => {})";
+	it('can\'t break on quotes', () => {});
+});`,
+			expected: "Dashboard(can't break on quotes)",
+		},
+		{
+			name: "Each and skipped tests",
+			source: "describe('Login', () => {\n" +
+				"\tit.each([{ role: 'admin' }])('should log in as $role', async ({ role }) => {\n\t\t// TODO: Implement test\n\t});\n" +
+				"\t[{ role: 'admin' }].forEach(({ role }) => {\n\t\tit(`should log out as ${role}`, async () => {});\n\t});\n" +
+				"\txit('should be skipped', () => {});\n" +
+				"\ttest.todo('should be written');\n" +
+				"});",
+			expected: "Login(should log in as <role>* should log out as <role> should be skipped should be written*)",
+		},
+		{
+			name:     "Typed each",
+			source:   "it.each<[number, string]>([[1, 'a']])('should total %s (%s)', async (quantity, plan) => {\n\t// TODO: Implement test\n});",
+			expected: "should total %s (%s)*",
+		},
+		{
+			name: "Regular expressions",
+			source: "describe('Form', () => {\n" +
+				"\tit('should match', () => {\n\t\texpect(text).toMatch(/don't \\/ \"[)}]/i);\n\t\tconst half = total / 2 / count;\n\t});\n" +
+				"\tit('should run after', () => {});\n" +
+				"});",
+			expected: "Form(should match should run after)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := specShape(parseSpecBlocks(tt.source)); result != tt.expected {
+				t.Errorf("parseSpecBlocks() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseSpecBlocksOffsets(t *testing.T) {
+	source := "describe('Dashboard', () => {\n\txit('should render', () => {});\n});\n"
+	blocks := parseSpecBlocks(source)
+
+	describe := blocks[0]
+	if describe.Start != 0 || source[describe.BodyEnd] != '}' || describe.End != len(source)-2 {
+		t.Errorf("Unexpected describe offsets %d, %d, %d", describe.Start, describe.BodyEnd, describe.End)
+	}
+
+	test := describe.Children[0]
+	if test.Line != 2 || test.Callee != "xit" || !test.isSkipped() || test.isDescribe() {
+		t.Errorf("Expected a skipped test on line 2, got %+v", test)
+	}
+}
+
+func TestParseSpecBlocksExamples(t *testing.T) {
+	tests := []struct {
+		name     string
+		table    string
+		expected []specRow
+	}{
+		{"Arrays", "[[1, 'a'], [2, \"b\"]]", []specRow{{{value: "1"}, {value: "a"}}, {{value: "2"}, {value: "b"}}}},
+		{"Objects", "[{ count: 1, 'label': 'a' }]", []specRow{{{"count", "1"}, {"label", "a"}}}},
+		{"Single values", "[1, 2]", []specRow{{{value: "1"}}, {{value: "2"}}}},
+		{"Empty key", "[{ : 1, label: 'a' }]", []specRow{{{"label", "a"}}}},
+		{"Not a literal", "rows", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks := parseSpecBlocks("it.each(" + tt.table + ")('should count $count', () => {});\n")
+			if len(blocks) != 1 {
+				t.Fatalf("parseSpecBlocks() found %d blocks, want 1", len(blocks))
+			}
+			if !reflect.DeepEqual(blocks[0].Examples, tt.expected) {
+				t.Errorf("parseSpecBlocks() examples = %+v, want %+v", blocks[0].Examples, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// acFile is the structured AC format, written in YAML or JSON:
+//
+//	link: https://example.atlassian.net/browse/DASH-12
+//	suites:
+//	  - title: Widgets
+//	    cases:
+//	      - id: DASH-13
+//	        title: Shows the revenue
+//	        tags: [smoke]
+//	        skip: true
+type acFile struct {
+	Link     string    `yaml:"link"`
+	Language string    `yaml:"language"`
+	Suites   []acSuite `yaml:"suites"`
+	Cases    []acCase  `yaml:"cases"`
+}
+
+// acSuite is a group of cases, generated as a describe block
+type acSuite struct {
+	ID            string     `yaml:"id"`
+	Title         string     `yaml:"title"`
+	Tags          stringList `yaml:"tags"`
+	Notes         stringList `yaml:"notes"`
+	Preconditions stringList `yaml:"preconditions"`
+	Skip          bool       `yaml:"skip"`
+	Suites        []acSuite  `yaml:"suites"`
+	Cases         []acCase   `yaml:"cases"`
+	line          int
+}
+
+// acCase is a single test case, generated as an it block
+type acCase struct {
+	ID            string     `yaml:"id"`
+	Title         string     `yaml:"title"`
+	Priority      string     `yaml:"priority"`
+	Tags          stringList `yaml:"tags"`
+	Notes         stringList `yaml:"notes"`
+	Preconditions stringList `yaml:"preconditions"`
+	Steps         stringList `yaml:"steps"`
+	Expected      stringList `yaml:"expected"`
+	Skip          bool       `yaml:"skip"`
+	line          int
+}
+
+var (
+	acFileFields  = []string{"link", "language", "suites", "cases"}
+	acSuiteFields = []string{"id", "title", "tags", "notes", "preconditions", "skip", "suites", "cases"}
+	acCaseFields  = []string{"id", "title", "priority", "tags", "notes", "preconditions", "steps", "expected", "skip"}
+)
+
+func (f *acFile) UnmarshalYAML(value *yaml.Node) error {
+	if err := checkFields(value, acFileFields); err != nil {
+		return err
+	}
+	type plain acFile
+	return value.Decode((*plain)(f))
+}
+
+func (s *acSuite) UnmarshalYAML(value *yaml.Node) error {
+	if err := checkFields(value, acSuiteFields); err != nil {
+		return err
+	}
+	type plain acSuite
+	if err := value.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	s.line = value.Line
+	return nil
+}
+
+func (c *acCase) UnmarshalYAML(value *yaml.Node) error {
+	if err := checkFields(value, acCaseFields); err != nil {
+		return err
+	}
+	type plain acCase
+	if err := value.Decode((*plain)(c)); err != nil {
+		return err
+	}
+	c.line = value.Line
+	return nil
+}
+
+// checkFields rejects mapping keys outside fields, so that typos such as
+// "skipped" don't go unnoticed
+func checkFields(value *yaml.Node, fields []string) error {
+	if value.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(value.Content); i += 2 {
+		if key := value.Content[i]; !slices.Contains(fields, key.Value) {
+			return fmt.Errorf("line %d: unknown field %q", key.Line, key.Value)
+		}
+	}
+	return nil
+}
+
+// isStructuredAcs reports whether text is a structured AC file, a YAML or
+// JSON mapping with suites or cases
+func isStructuredAcs(text string) bool {
+	var fields map[string]any
+	if yaml.Unmarshal([]byte(text), &fields) != nil {
+		return false
+	}
+	_, suites := fields["suites"]
+	_, cases := fields["cases"]
+	return suites || cases
+}
+
+// parseStructuredAcs builds the AC tree from a structured AC file
+func parseStructuredAcs(text string) acDocument {
+	var file acFile
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(text)))
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return acDocument{Diagnostics: []acDiagnostic{{1, "", "is not a valid AC file: " + err.Error()}}}
+	}
+
+	document := acDocument{Link: file.Link, Language: file.Language}
+	document.Nodes = structuredNodes(&document, file.Suites, file.Cases, 1)
+	return document
+}
+
+func structuredNodes(document *acDocument, suites []acSuite, cases []acCase, level int) []*acNode {
+	var nodes []*acNode
+
+	for _, suite := range suites {
+		if strings.TrimSpace(suite.Title) == "" {
+			document.Diagnostics = append(document.Diagnostics, acDiagnostic{suite.line, suite.ID, "is a suite without a title"})
+			continue
+		}
+
+		node := &acNode{
+			Title:         strings.TrimSpace(suite.Title),
+			Kind:          acKindSuite,
+			ID:            suite.ID,
+			Level:         level,
+			Line:          suite.line,
+			Tags:          normalizeTags(suite.Tags),
+			Notes:         splitLines(suite.Notes),
+			Preconditions: splitLines(suite.Preconditions),
+			Skip:          suite.Skip,
+		}
+		node.Children = structuredNodes(document, suite.Suites, suite.Cases, level+1)
+		nodes = append(nodes, node)
+	}
+
+	for _, testCase := range cases {
+		if strings.TrimSpace(testCase.Title) == "" {
+			document.Diagnostics = append(document.Diagnostics, acDiagnostic{testCase.line, testCase.ID, "is a case without a title"})
+			continue
+		}
+
+		node := &acNode{
+			Title:         strings.TrimSpace(testCase.Title),
+			ID:            testCase.ID,
+			Level:         level,
+			Line:          testCase.line,
+			Priority:      testCase.Priority,
+			Tags:          normalizeTags(testCase.Tags),
+			Notes:         splitLines(testCase.Notes),
+			Preconditions: splitLines(testCase.Preconditions),
+			Skip:          testCase.Skip,
+		}
+		appendTestSteps(node, strings.Join(testCase.Steps, "\n"), strings.Join(testCase.Expected, "\n"))
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// normalizeTags prefixes tags with @ as they're written in test titles
+func normalizeTags(tags []string) []string {
+	var result []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag == "" {
+			continue
+		}
+		if !strings.HasPrefix(tag, "@") {
+			tag = "@" + tag
+		}
+		result = append(result, tag)
+	}
+	return result
+}
+
+// splitLines splits multi-line values into their non-empty lines
+func splitLines(values []string) []string {
+	var lines []string
+	for _, value := range values {
+		lines = append(lines, nonEmptyLines(value)...)
+	}
+	return lines
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const structuredYAML = `link: https://example.atlassian.net/browse/DASH-12
+suites:
+  - title: Widgets
+    tags: [widgets]
+    cases:
+      - id: DASH-13
+        title: Shows the revenue
+        priority: High
+        tags: ["@smoke", regression]
+        preconditions: Logged in
+        steps:
+          - Open the dashboard
+        expected:
+          - The revenue widget is shown
+      - title: Shows the orders
+        skip: true
+  - id: DASH-20
+    title: Settings
+cases:
+  - title: Saves the layout
+`
+
+func TestParseStructuredAcs(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		text     string
+		expected string
+	}{
+		{
+			name:     "YAML file",
+			fileName: "acs.yaml",
+			text:     structuredYAML,
+			expected: "Widgets(Shows the revenue Shows the orders) Settings() Saves the layout",
+		},
+		{
+			name:     "JSON file",
+			fileName: "acs.json",
+			text:     `{"suites": [{"title": "Widgets", "suites": [{"title": "Revenue", "cases": [{"title": "Shows the total"}]}]}]}`,
+			expected: "Widgets(Revenue(Shows the total))",
+		},
+		{
+			name:     "Pasted YAML",
+			text:     "cases:\n  - title: Shows the revenue\n",
+			expected: "Shows the revenue",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := outlineShape(parseAcText(tt.fileName, tt.text, acsConfig{}).Nodes); result != tt.expected {
+				t.Errorf("parseAcText() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseStructuredAcsFields(t *testing.T) {
+	document := parseStructuredAcs(structuredYAML)
+
+	if document.Link != "https://example.atlassian.net/browse/DASH-12" {
+		t.Errorf("Expected the link of the file, got %q", document.Link)
+	}
+
+	revenue := document.Nodes[0].Children[0]
+	expected := &acNode{
+		Title:         "Shows the revenue",
+		Level:         2,
+		Line:          6,
+		ID:            "DASH-13",
+		Priority:      "High",
+		Tags:          []string{"@smoke", "@regression"},
+		Preconditions: []string{"Logged in"},
+		Steps:         []acStep{{stepAction, "Open the dashboard"}, {stepExpected, "The revenue widget is shown"}},
+	}
+	if !reflect.DeepEqual(revenue, expected) {
+		t.Errorf("parseStructuredAcs() case = %+v, want %+v", revenue, expected)
+	}
+}
+
+func TestParseStructuredAcsDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []acDiagnostic
+	}{
+		{
+			name:     "Case without a title",
+			text:     "cases:\n  - id: DASH-1\n  - title: Shows the revenue\n",
+			expected: []acDiagnostic{{2, "DASH-1", "is a case without a title"}},
+		},
+		{
+			name:     "Unknown field",
+			text:     "cases:\n  - title: Shows the revenue\n    skipped: true\n",
+			expected: []acDiagnostic{{1, "", `is not a valid AC file: line 3: unknown field "skipped"`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := parseStructuredAcs(tt.text).Diagnostics; !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("parseStructuredAcs() diagnostics = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderStructuredAcs(t *testing.T) {
+	tests := []struct {
+		name      string
+		framework testFramework
+		expected  []string
+	}{
+		{
+			name:      "Jest",
+			framework: frameworkJest,
+			expected: []string{
+				"describe('Widgets @widgets', () => {",
+				"it('[DASH-13] should show the revenue @smoke @regression', async () => {",
+				"// Priority: High",
+				"it.skip('should show the orders', async () => {",
+				"describe('[DASH-20] Settings', () => {",
+			},
+		},
+		{
+			name:      "Karma",
+			framework: frameworkKarma,
+			expected:  []string{"xit('should show the orders', async () => {"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := defaultSpecSettings()
+			settings.framework = tt.framework
+
+			result := renderAcs(parseStructuredAcs(structuredYAML).Nodes, settings)
+			for _, phrase := range tt.expected {
+				if !strings.Contains(result, phrase) {
+					t.Errorf("renderAcs() does not contain expected phrase %q in:\n%s", phrase, result)
+				}
+			}
+		})
+	}
+}
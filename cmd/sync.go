@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Comment added above the tests whose criterion was removed from the ACs
+const syncRemovedComment = "// Removed from the ACs"
+
+// acDrift lists the differences between ACs and the tests generated from an
+// earlier version of them
+type acDrift struct {
+	Added    []driftEntry
+	Removed  []driftEntry
+	Reworded []driftEntry
+	// Unchanged is the number of criteria with the same hash as their test
+	Unchanged int
+}
+
+// driftEntry is a criterion, a test, or both when the criterion was reworded
+type driftEntry struct {
+	// Path is the title of the criterion or test, after its describe titles
+	Path  string
+	node  *acNode
+	block *specBlock
+}
+
+// acCriterion is a criterion with the path of its groups
+type acCriterion struct {
+	path []string
+	node *acNode
+}
+
+// specTest is an existing test with the path of its describe blocks
+type specTest struct {
+	path  []string
+	block *specBlock
+}
+
+var syncOptions generateOptions
+var syncSkipRemoved bool
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <spec>",
+	Short: "Compare the ACs of a ticket with the tests generated from them",
+	Long: `Compare ACs, read from a file, a ticket or the form, with the tests of a spec
+generated from an earlier version of them, and list the criteria that were
+added, removed or reworded since. Tests are matched by the hash of their
+criterion, written after each generated test.`,
+	Example: `
+	ng-spec sync src/app/dashboard/dashboard.component.spec.ts --ticket DASH-12
+	ng-spec sync dashboard.component.spec.ts --acs-file acs.md --skip-removed
+	`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := syncCommand(cmd.OutOrStdout(), args[0], syncOptions, syncSkipRemoved, userInput{}); err != nil {
+			printCommandError(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	syncCmd.Flags().StringVar(&syncOptions.acsFile, "acs-file", "", "read the ACs from a file instead of the form, - reads stdin")
+	syncCmd.Flags().StringVar(&syncOptions.ticket, "ticket", "", "fetch the ACs of a ticket from the issue tracker configured in ng-spec.json")
+	syncCmd.Flags().BoolVar(&syncSkipRemoved, "skip-removed", false, "skip the tests of removed criteria and add a comment above them")
+
+	rootCmd.AddCommand(syncCmd)
+}
+
+// syncCommand prints the drift between the ACs and a spec, and skips the
+// tests of removed criteria when asked to
+func syncCommand(w io.Writer, specFile string, options generateOptions, skipRemoved bool, input userConfirmationInput) error {
+	specFile, err := filepath.Abs(specFile)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(specFile)
+	if err != nil {
+		return err
+	}
+	source := string(data)
+
+	settings, err := acsSettings(specFile)
+	if err != nil {
+		return err
+	}
+
+	_, document, err := readAcs(options, settings, input)
+	if err != nil {
+		return err
+	}
+
+	settings = settings.forAcs(document)
+	drift := compareAcs(document.Nodes, parseSpecBlocks(source), settings)
+	printDrift(w, drift)
+
+	if !skipRemoved || len(drift.Removed) == 0 {
+		return nil
+	}
+
+	skipped, count := skipRemovedTests(source, drift.Removed, settings)
+	if err := os.WriteFile(specFile, []byte(skipped), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Skipped %d removed test(s) in %s\n", count, specFile)
+	return nil
+}
+
+// acHash returns the hash of a criterion, normalised so that changes in case,
+// spacing and trailing punctuation don't count as rewording
+func acHash(node *acNode) string {
+	var lines []string
+	add := func(texts ...string) {
+		for _, text := range texts {
+			text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+			lines = append(lines, strings.TrimRight(text, ".,;:!"))
+		}
+	}
+
+	add(node.Title)
+	add(node.Preconditions...)
+	add(node.Notes...)
+	for _, step := range node.Steps {
+		add(step.Keyword + " " + step.Text)
+	}
+	if node.Examples != nil {
+		add(node.Examples.Header...)
+		for _, row := range node.Examples.Rows {
+			add(strings.Join(row, "|"))
+		}
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:4])
+}
+
+// acHashComment returns the comment written after a generated test
+func acHashComment(node *acNode) string {
+	return "ac:" + acHash(node)
+}
+
+// compareAcs matches criteria with the tests generated from them: by hash
+// when unchanged, then by test title when only the details changed, then by
+// titles sharing more than half their words. Tests without a hash are
+// hand-written and left out.
+func compareAcs(nodes []*acNode, blocks []*specBlock, settings specSettings) acDrift {
+	var criteria []acCriterion
+	var collectCriteria func(nodes []*acNode, path []string)
+	collectCriteria = func(nodes []*acNode, path []string) {
+		for _, node := range nodes {
+			switch {
+			case node.Kind == acKindBackground:
+			case node.isGroup():
+				collectCriteria(node.Children, slices.Concat(path, []string{groupTitle(node.Title)}))
+			default:
+				criteria = append(criteria, acCriterion{path, node})
+			}
+		}
+	}
+	collectCriteria(nodes, nil)
+
+	var tests []specTest
+	var collectTests func(blocks []*specBlock, path []string)
+	collectTests = func(blocks []*specBlock, path []string) {
+		for _, block := range blocks {
+			switch {
+			case block.isDescribe():
+				collectTests(block.Children, slices.Concat(path, []string{block.Title}))
+			case block.Hash != "":
+				tests = append(tests, specTest{path, block})
+			}
+		}
+	}
+	collectTests(blocks, nil)
+
+	var drift acDrift
+	matched := make([]bool, len(tests))
+	var unmatched []acCriterion
+
+	for _, criterion := range criteria {
+		hash := acHash(criterion.node)
+		// Criteria written twice have a test each, so matched tests are skipped
+		i := -1
+		for j, test := range tests {
+			if !matched[j] && test.block.Hash == hash {
+				i = j
+				break
+			}
+		}
+		if i >= 0 {
+			matched[i] = true
+			drift.Unchanged++
+			continue
+		}
+		unmatched = append(unmatched, criterion)
+	}
+
+	// Titles are compared without the prefix every test title starts with
+	prefix := strings.ToLower(settings.titlePrefix()) + " "
+	for _, criterion := range unmatched {
+		title := mergeKey(acsTestName(criterion.node, settings))
+		best, bestScore := -1, 0.5
+		for i, test := range tests {
+			if matched[i] {
+				continue
+			}
+			if mergeKey(test.block.Title) == title {
+				best = i
+				break
+			}
+			score := titleSimilarity(strings.TrimPrefix(title, prefix), strings.TrimPrefix(mergeKey(test.block.Title), prefix))
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		path := strings.Join(slices.Concat(criterion.path, []string{criterion.node.Title}), " > ")
+		if best < 0 {
+			drift.Added = append(drift.Added, driftEntry{Path: path, node: criterion.node})
+			continue
+		}
+		matched[best] = true
+		drift.Reworded = append(drift.Reworded, driftEntry{Path: path, node: criterion.node, block: tests[best].block})
+	}
+
+	for i, test := range tests {
+		if !matched[i] {
+			// The outermost describe is the component's, which ACs don't name
+			path := test.path[min(1, len(test.path)):]
+			drift.Removed = append(drift.Removed, driftEntry{
+				Path:  strings.Join(slices.Concat(path, []string{test.block.Title}), " > "),
+				block: test.block,
+			})
+		}
+	}
+
+	return drift
+}
+
+// titleSimilarity returns the share of words two titles have in common
+func titleSimilarity(a, b string) float64 {
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	total, common := len(wordsA)+len(wordsB), 0
+	for _, word := range wordsA {
+		if i := slices.Index(wordsB, word); i >= 0 {
+			wordsB = slices.Delete(wordsB, i, i+1)
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(total)
+}
+
+func printDrift(w io.Writer, drift acDrift) {
+	if len(drift.Added)+len(drift.Removed)+len(drift.Reworded) == 0 {
+		fmt.Fprintf(w, "No drift, the %d criteria match the spec\n", drift.Unchanged)
+		return
+	}
+
+	if len(drift.Added) > 0 {
+		fmt.Fprintf(w, "Added (%d):\n", len(drift.Added))
+		for _, entry := range drift.Added {
+			fmt.Fprintf(w, "  + %s\n", entry.Path)
+		}
+	}
+	if len(drift.Removed) > 0 {
+		fmt.Fprintf(w, "Removed (%d):\n", len(drift.Removed))
+		for _, entry := range drift.Removed {
+			fmt.Fprintf(w, "  - %s (line %d)\n", entry.Path, entry.block.Line)
+		}
+	}
+	if len(drift.Reworded) > 0 {
+		fmt.Fprintf(w, "Reworded (%d):\n", len(drift.Reworded))
+		for _, entry := range drift.Reworded {
+			fmt.Fprintf(w, "  ~ %s (line %d, was %q)\n", entry.Path, entry.block.Line, entry.block.Title)
+		}
+	}
+}
+
+// skipRemovedTests skips the tests of removed criteria and adds a comment
+// above them. Tests that are already skipped are left alone.
+func skipRemovedTests(source string, removed []driftEntry, settings specSettings) (string, int) {
+	var edits []specEdit
+	for _, entry := range removed {
+		block := entry.block
+		if block.isSkipped() {
+			continue
+		}
+
+		// A focused test can't also be skipped, so .only is dropped while
+		// modifiers such as .each are kept
+		parts := strings.Split(block.Callee, ".")
+		callee := acsCallee(strings.TrimPrefix(parts[0], "f"), true, settings)
+		for _, modifier := range parts[1:] {
+			if modifier != "only" {
+				callee += "." + modifier
+			}
+		}
+
+		lineStart := strings.LastIndexByte(source[:block.Start], '\n') + 1
+		if indent := source[lineStart:block.Start]; strings.TrimSpace(indent) == "" {
+			edits = append(edits, specEdit{lineStart, len(indent) + len(block.Callee), indent + syncRemovedComment + "\n" + indent + callee})
+		} else {
+			edits = append(edits, specEdit{block.Start, len(block.Callee), callee})
+		}
+	}
+
+	return applySpecEdits(source, edits), len(edits)
+}
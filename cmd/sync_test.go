@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAcHash(t *testing.T) {
+	base := &acNode{Title: "Shows revenue", Steps: []acStep{{Keyword: "Then", Text: "the total is shown"}}}
+
+	tests := []struct {
+		name     string
+		node     *acNode
+		expected bool
+	}{
+		{
+			name:     "Case, spacing and punctuation",
+			node:     &acNode{Title: "shows  REVENUE.", Steps: []acStep{{Keyword: "Then", Text: "The total is shown"}}},
+			expected: true,
+		},
+		{
+			name:     "Reworded title",
+			node:     &acNode{Title: "Shows the revenue", Steps: []acStep{{Keyword: "Then", Text: "the total is shown"}}},
+			expected: false,
+		},
+		{
+			name:     "Changed step",
+			node:     &acNode{Title: "Shows revenue", Steps: []acStep{{Keyword: "Then", Text: "the total is hidden"}}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := acHash(tt.node) == acHash(base); result != tt.expected {
+				t.Errorf("acHash() equal = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+
+	if hash := acHash(base); len(hash) != 8 {
+		t.Errorf("acHash() = %q, want 8 hex characters", hash)
+	}
+}
+
+func TestCompareAcs(t *testing.T) {
+	settings := defaultSpecSettings()
+	document := parseAcText("", "1. Widgets\na. Shows revenue\nb. Shows orders\nc. Shows the top customers\n2. Settings\na. Saves the layout", settings.config.Acs)
+	spec := integrateAcsWithTemplate(createTemplate("dashboard", settings), "DASH-1", renderAcs(document.Nodes, settings.forAcs(document)))
+
+	// A hand-written test, which has no hash
+	spec = strings.Replace(spec, "// TODO: Implement test", "// TODO: Implement test\n});\n\nit('should render the header', () => {", 1)
+
+	blocks := parseSpecBlocks(spec)
+	nodes := parseAcText("", "1. Widgets\na. shows revenue.\nb. Shows the orders placed today\nc. Shows the best customers\nd. Shows alerts", acsConfig{}).Nodes
+	drift := compareAcs(nodes, blocks, settings)
+
+	if drift.Unchanged != 1 {
+		t.Errorf("compareAcs() unchanged = %d, want 1", drift.Unchanged)
+	}
+
+	paths := func(entries []driftEntry) string {
+		var result []string
+		for _, entry := range entries {
+			result = append(result, entry.Path)
+		}
+		return strings.Join(result, ", ")
+	}
+	expected := map[string][2]string{
+		"added":    {paths(drift.Added), "Widgets > Shows alerts"},
+		"reworded": {paths(drift.Reworded), "Widgets > Shows the orders placed today, Widgets > Shows the best customers"},
+		"removed":  {paths(drift.Removed), "Settings > should save the layout"},
+	}
+	for name, values := range expected {
+		if values[0] != values[1] {
+			t.Errorf("compareAcs() %s = %q, want %q", name, values[0], values[1])
+		}
+	}
+}
+
+func TestCompareAcsRepeatedCriteria(t *testing.T) {
+	settings := defaultSpecSettings()
+	acs := "1. Widgets\na. Shows revenue\n2. Reports\na. Shows revenue"
+	document := parseAcText("", acs, settings.config.Acs)
+	blocks := parseSpecBlocks(integrateAcsWithTemplate(createTemplate("dashboard", settings), "DASH-1", renderAcs(document.Nodes, settings.forAcs(document))))
+
+	drift := compareAcs(parseAcText("", acs, acsConfig{}).Nodes, blocks, settings)
+	if drift.Unchanged != 2 || len(drift.Added)+len(drift.Reworded)+len(drift.Removed) > 0 {
+		t.Errorf("compareAcs() = %+v, want both criteria unchanged", drift)
+	}
+}
+
+func TestSkipRemovedTests(t *testing.T) {
+	source := "describe('DashboardComponent', () => {\n" +
+		"\tit('should show orders', async () => {\n" +
+		"\t\t// TODO: Implement test\n" +
+		"\t}); // ac:0000abcd\n" +
+		"\n" +
+		"\txit('should show alerts', async () => {}); // ac:0000abce\n" +
+		"});\n"
+	focused := strings.Replace(source, "\tit(", "\tit.only(", 1)
+	focusedEach := strings.Replace(source, "\tit(", "\tit.only.each([[1]])(", 1)
+	focusedJasmine := strings.Replace(source, "\tit(", "\tfit(", 1)
+
+	tests := []struct {
+		name      string
+		framework testFramework
+		source    string
+		expected  string
+	}{
+		{
+			name:      "Jest",
+			framework: frameworkJest,
+			source:    source,
+			expected:  "\t" + syncRemovedComment + "\n\tit.skip('should show orders', async () => {\n",
+		},
+		{
+			name:      "Jasmine",
+			framework: frameworkKarma,
+			source:    source,
+			expected:  "\t" + syncRemovedComment + "\n\txit('should show orders', async () => {\n",
+		},
+		{
+			name:      "Focused",
+			framework: frameworkJest,
+			source:    focused,
+			expected:  "\t" + syncRemovedComment + "\n\tit.skip('should show orders', async () => {\n",
+		},
+		{
+			name:      "Focused each",
+			framework: frameworkJest,
+			source:    focusedEach,
+			expected:  "\t" + syncRemovedComment + "\n\tit.skip.each([[1]])('should show orders', async () => {\n",
+		},
+		{
+			name:      "Focused Jasmine",
+			framework: frameworkKarma,
+			source:    focusedJasmine,
+			expected:  "\t" + syncRemovedComment + "\n\txit('should show orders', async () => {\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := defaultSpecSettings()
+			settings.framework = tt.framework
+
+			drift := compareAcs(nil, parseSpecBlocks(tt.source), settings)
+			result, count := skipRemovedTests(tt.source, drift.Removed, settings)
+
+			if count != 1 {
+				t.Errorf("skipRemovedTests() skipped %d tests, want 1", count)
+			}
+			if !strings.Contains(result, tt.expected) {
+				t.Errorf("skipRemovedTests() does not contain %q in:\n%s", tt.expected, result)
+			}
+			if strings.Count(result, syncRemovedComment) != 1 {
+				t.Errorf("skipRemovedTests() should leave skipped tests alone:\n%s", result)
+			}
+		})
+	}
+}
+
+func TestSyncCommand(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	settings := defaultSpecSettings()
+	specFile := filepath.Join(dir, "dashboard.component.spec.ts")
+	document := parseAcText("", "1. Shows revenue\n2. Shows orders", settings.config.Acs)
+	spec := integrateAcsWithTemplate(createTemplate("dashboard", settings), "", renderAcs(document.Nodes, settings.forAcs(document)))
+	if err := os.WriteFile(specFile, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	acsFile := filepath.Join(dir, "acs.md")
+	if err := os.WriteFile(acsFile, []byte("1. Shows revenue\n2. Shows alerts\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	if err := syncCommand(&out, specFile, generateOptions{acsFile: acsFile}, true, userInput{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, phrase := range []string{"Added (1):\n  + Shows alerts\n", "Removed (1):\n  - should show orders (line ", "Skipped 1 removed test(s)"} {
+		if !strings.Contains(out.String(), phrase) {
+			t.Errorf("syncCommand() output does not contain %q in:\n%s", phrase, out.String())
+		}
+	}
+
+	data, err := os.ReadFile(specFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), syncRemovedComment+"\n\tit.skip('should show orders'") {
+		t.Errorf("syncCommand() should skip the removed test, got:\n%s", data)
+	}
+}
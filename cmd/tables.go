@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	tableSeparatorRegex = regexp.MustCompile(`^\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?$`)
+	tableNumberRegex    = regexp.MustCompile(`^-?(?:0|[1-9]\d*)(?:\.\d+)?$`)
+)
+
+// extractTables moves the first table in the notes of each criterion to its
+// examples, so that the test runs once per row. Tables are Markdown or Jira
+// pipe tables, or tab-separated rows pasted from a spreadsheet, and their
+// first row is the header.
+func extractTables(nodes []*acNode) {
+	for _, node := range nodes {
+		walkAcNodes(node, func(node *acNode) {
+			if node.Examples != nil || node.isGroup() || node.Kind == acKindBackground {
+				return
+			}
+
+			for start := range node.Notes {
+				rows, end := readNoteTable(node.Notes, start)
+				if len(rows) < 2 {
+					continue
+				}
+				node.Examples = &acTable{Header: rows[0], Rows: rows[1:]}
+				node.Notes = slices.Delete(node.Notes, start, end)
+				return
+			}
+		})
+	}
+}
+
+// readNoteTable reads the rows of a table starting at notes[start] and
+// returns them with the index of the first note after the table
+func readNoteTable(notes []string, start int) ([][]string, int) {
+	pipe := strings.HasPrefix(notes[start], "|")
+	if !pipe && !strings.Contains(notes[start], "\t") {
+		return nil, start
+	}
+
+	var rows [][]string
+	i := start
+	for ; i < len(notes); i++ {
+		line := notes[i]
+
+		var cells []string
+		switch {
+		case pipe && tableSeparatorRegex.MatchString(line):
+			continue
+		case pipe && strings.HasPrefix(line, "||"):
+			// Jira marks header cells with double pipes
+			cells = gherkinTableCells(strings.ReplaceAll(line, "||", "|"))
+		case pipe && strings.HasPrefix(line, "|"):
+			cells = gherkinTableCells(line)
+		case !pipe && strings.Contains(line, "\t"):
+			for _, cell := range strings.Split(line, "\t") {
+				cells = append(cells, strings.TrimSpace(cell))
+			}
+			// Trailing empty cells were trimmed with the line
+			for len(rows) > 0 && len(cells) < len(rows[0]) {
+				cells = append(cells, "")
+			}
+		}
+
+		if cells == nil || (len(rows) > 0 && len(cells) != len(rows[0])) {
+			break
+		}
+		rows = append(rows, cells)
+	}
+
+	return rows, i
+}
+
+// tableColumnType returns the TypeScript type shared by the values of a
+// column: number or boolean when they all are, string otherwise
+func tableColumnType(rows [][]string, column int) string {
+	numbers, booleans := true, true
+	for _, row := range rows {
+		value := ""
+		if column < len(row) {
+			value = row[column]
+		}
+		numbers = numbers && tableNumberRegex.MatchString(value)
+		booleans = booleans && (value == "true" || value == "false")
+	}
+
+	switch {
+	case numbers:
+		return "number"
+	case booleans:
+		return "boolean"
+	}
+	return "string"
+}
+
+// tableValue returns the literal for a value of a column of the given type
+func tableValue(value, typ string) tsExpr {
+	if typ == "string" {
+		return tsString(value)
+	}
+	return tsRaw(value)
+}
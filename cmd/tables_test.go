@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractTables(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		acs      string
+		expected *acTable
+		notes    []string
+	}{
+		{
+			name:     "Markdown pipe table",
+			acs:      "1. Applies the discount\n   Rates per quantity:\n   | quantity | total |\n   |---:|:---|\n   | 1 | 10 |\n   | 2 | 18 |",
+			expected: &acTable{Header: []string{"quantity", "total"}, Rows: [][]string{{"1", "10"}, {"2", "18"}}},
+			notes:    []string{"Rates per quantity:"},
+		},
+		{
+			name:     "Jira table",
+			acs:      "1. Applies the discount\n|| quantity || total ||\n| 1 | 10 |",
+			expected: &acTable{Header: []string{"quantity", "total"}, Rows: [][]string{{"1", "10"}}},
+		},
+		{
+			name:     "Tab-separated rows",
+			acs:      "1. Shows the price\nplan\tprice\tnote\nBasic\t10\t\nPro\t20\tBest value\nPrices exclude VAT",
+			expected: &acTable{Header: []string{"plan", "price", "note"}, Rows: [][]string{{"Basic", "10", ""}, {"Pro", "20", "Best value"}}},
+			notes:    []string{"Prices exclude VAT"},
+		},
+		{
+			name:     "Markdown cells",
+			fileName: "acs.md",
+			acs:      "# Pricing\n\n- Shows the price\n\n  | plan | label |\n  | --- | --- |\n  | `pro` | **Pro** |",
+			expected: &acTable{Header: []string{"plan", "label"}, Rows: [][]string{{"pro", "Pro"}}},
+		},
+		{
+			name:  "Header only",
+			acs:   "1. Shows the price\n| plan | price |",
+			notes: []string{"| plan | price |"},
+		},
+		{
+			name:  "Single column",
+			acs:   "1. Shows the price\nplan\nBasic",
+			notes: []string{"plan", "Basic"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var node *acNode
+			for _, n := range parseAcText(tt.fileName, tt.acs, acsConfig{}).Nodes {
+				walkAcNodes(n, func(n *acNode) {
+					if !n.isGroup() {
+						node = n
+					}
+				})
+			}
+			if node == nil {
+				t.Fatal("parseAcText() returned no criterion")
+			}
+
+			if !reflect.DeepEqual(node.Examples, tt.expected) {
+				t.Errorf("Examples = %+v, want %+v", node.Examples, tt.expected)
+			}
+			if len(node.Notes) > 0 || len(tt.notes) > 0 {
+				if !reflect.DeepEqual(node.Notes, tt.notes) {
+					t.Errorf("Notes = %q, want %q", node.Notes, tt.notes)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractTablesSkipsGroups(t *testing.T) {
+	nodes := parseAcText("", "1. Pricing\n| plan | price |\n| Basic | 10 |\na. Shows the price", acsConfig{}).Nodes
+
+	if nodes[0].Examples != nil || len(nodes[0].Notes) != 2 {
+		t.Errorf("A table under a group should stay in its notes, got %+v", nodes[0])
+	}
+}
+
+func TestTableColumnType(t *testing.T) {
+	rows := [][]string{
+		{"1", "true", "007", "-2.5", "yes"},
+		{"20", "false", "8", "0", "no"},
+	}
+	expected := []string{"number", "boolean", "string", "number", "string"}
+
+	for column, want := range expected {
+		if result := tableColumnType(rows, column); result != want {
+			t.Errorf("tableColumnType(%d) = %q, want %q", column, result, want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// titlesConfig shapes the titles of the generated tests
+type titlesConfig struct {
+	// Prefix starts every test title, "should" by default and none when set
+	// to an empty string
+	Prefix *string `json:"prefix"`
+	// Language of the ACs when they don't declare one, en by default
+	Language string `json:"language"`
+	// Prefixes sets the prefix per language, such as {"de": "sollte"}
+	Prefixes map[string]string `json:"prefixes"`
+}
+
+// Language the built-in title rules are written for
+const defaultTitleLanguage = "en"
+
+var (
+	// Ticket keys, AC numbers and issue references around a title, such as
+	// "[DASH-12]", "AC 1.2:" or "(#42)"
+	titleTicketPrefixRegex = regexp.MustCompile(`^(?:[\[(]?(?:[A-Z][A-Z0-9]+-\d+|#\d+)[\])]?|AC\s*-?\s*\d+(?:\.\d+)*)\s*[:.)\-–—]?\s+`)
+	titleTicketSuffixRegex = regexp.MustCompile(`\s+[\[(]?(?:[A-Z][A-Z0-9]+-\d+|#\d+)[\])]?$`)
+	titlePunctuationRegex  = regexp.MustCompile(`\s*[.,;:!]+$`)
+	// Modal verbs repeating the prefix, as in "Should display the total",
+	// and their negations, as in "Cannot delete items"
+	titleModalRegex        = regexp.MustCompile(`(?i)^(?:it\s+)?(?:should|must|shall|will|can|may|needs? to|has to)\s+`)
+	titleNegatedModalRegex = regexp.MustCompile(`(?i)^(?:it\s+)?(?:can't|can’t|cannot|can not|may not|won't|won’t|does not|doesn't|doesn’t|is not able to|isn't able to|isn’t able to)\s+`)
+	// Abilities of a user, as in "User can delete items" or "User can't
+	// delete items"
+	titleAbilityRegex = regexp.MustCompile(`(?i)^(?:the\s+|an?\s+)?(user|customer|admin|administrator|visitor|guest|member)s?\s+(?:(can|may|is able to|are able to|should be able to|must be able to|will be able to)|(can't|can’t|cannot|can not|may not|is not able to|isn't able to|isn’t able to|are not able to|aren't able to|should not be able to|must not be able to|will not be able to|won't be able to))\s+(.+)$`)
+	// The user doing what a criterion describes, as in "The user clicks Save"
+	// or "I see the total"
+	titleSubjectRegex = regexp.MustCompile(`(?i)^(?:(?:the\s+|an?\s+)?(?:user|customer|admin|administrator|visitor|guest|member)s?|i)\s+`)
+)
+
+// titlePrefix returns the prefix of test titles for the language of the ACs.
+// Languages without a configured prefix get none, as the built-in "should"
+// only reads well in English.
+func (s specSettings) titlePrefix() string {
+	config := s.config.Titles
+	language := s.titleLanguage()
+	if prefix, ok := config.Prefixes[language]; ok {
+		return prefix
+	}
+	if config.Prefix != nil {
+		return *config.Prefix
+	}
+	if language == defaultTitleLanguage {
+		return "should"
+	}
+	return ""
+}
+
+// titleLanguage returns the language of the ACs, from the ACs themselves or
+// the workspace, without its region
+func (s specSettings) titleLanguage() string {
+	language := strings.ToLower(cmp.Or(s.language, s.config.Titles.Language, defaultTitleLanguage))
+	base, _, _ := strings.Cut(strings.ReplaceAll(language, "_", "-"), "-")
+	return base
+}
+
+// normalizeTestTitle turns the title of a criterion into the title of its
+// test: ticket keys and trailing punctuation are removed, and in English
+// modal verbs that would repeat the prefix are dropped, the user doing the
+// action is left out, a leading known verb takes its base form and abilities
+// such as "User can delete items" read "allow the user to delete items"
+func normalizeTestTitle(title string, settings specSettings) string {
+	title = strings.Join(strings.Fields(title), " ")
+	for {
+		trimmed := titleTicketPrefixRegex.ReplaceAllString(title, "")
+		trimmed = titleTicketSuffixRegex.ReplaceAllString(trimmed, "")
+		trimmed = titlePunctuationRegex.ReplaceAllString(trimmed, "")
+		if trimmed == title || trimmed == "" {
+			break
+		}
+		title = trimmed
+	}
+
+	prefix := settings.titlePrefix()
+	if prefix != "" && len(title) > len(prefix) && strings.EqualFold(title[:len(prefix)+1], prefix+" ") {
+		title = title[len(prefix)+1:]
+	}
+
+	if settings.titleLanguage() == defaultTitleLanguage {
+		negated := false
+		if prefix != "" {
+			if trimmed := titleNegatedModalRegex.ReplaceAllString(title, ""); trimmed != title {
+				title, negated = trimmed, true
+			} else {
+				title = titleModalRegex.ReplaceAllString(title, "")
+			}
+		}
+
+		switch matches := titleAbilityRegex.FindStringSubmatch(title); {
+		case prefix == "":
+		case matches != nil && matches[2] != "":
+			title = "allow the " + strings.ToLower(matches[1]) + " to " + matches[4]
+		case matches != nil:
+			title = "not allow the " + strings.ToLower(matches[1]) + " to " + matches[4]
+		case !negated:
+			if rest, ok := stripTitleSubject(title); ok {
+				title = baseFormFirstWord(rest, true)
+			} else {
+				title = baseFormFirstWord(title, false)
+			}
+		}
+		if negated {
+			title = "not " + lcFirstWord(title)
+		}
+	}
+
+	if prefix == "" {
+		return title
+	}
+	return prefix + " " + lcFirstWord(title)
+}
+
+// Third-person verbs that don't follow the spelling rules
+var titleIrregularVerbs = map[string]string{"is": "be", "are": "be", "has": "have", "does": "do", "goes": "go"}
+
+// Base forms of verbs ACs often start with. Only these are turned into their
+// base form, as telling a verb from a plural noun such as "Errors" takes more
+// than its spelling.
+var titleKnownVerbs = []string{
+	"accept", "add", "allow", "analyze", "apply", "calculate", "call", "cancel", "check", "choose",
+	"clear", "click", "close", "collapse", "confirm", "contain", "create", "delete", "disable",
+	"display", "download", "edit", "emit", "enable", "enter", "expand", "export", "fetch", "filter",
+	"focus", "format", "freeze", "group", "hide", "highlight", "import", "include", "keep", "limit",
+	"list", "load", "lock", "mark", "match", "move", "navigate", "notify", "open", "pick", "press",
+	"prevent", "print", "push", "redirect", "refresh", "reject", "remove", "render", "require",
+	"reset", "retry", "return", "save", "scroll", "search", "see", "select", "send", "show", "sort",
+	"store", "submit", "tap", "toggle", "type", "unlock", "update", "upload", "use", "validate", "warn",
+}
+
+// Known verbs whose third-person form is also a plural noun that opens
+// criteria, as in "Updates are saved". They're only taken for verbs when
+// the user comes before them or an article follows them.
+var titleNounVerbs = []string{
+	"call", "check", "download", "edit", "export", "filter", "format", "group", "highlight", "import",
+	"limit", "list", "lock", "mark", "match", "move", "print", "push", "return", "search", "sort",
+	"type", "update", "upload", "warn",
+}
+
+var (
+	// Words after a plural noun rather than a verb, as in "Filters are
+	// applied" or "Types of accounts"
+	titleNounFollowerRegex = regexp.MustCompile(`(?i)^(?:is|are|was|were|has|have|of)$`)
+	// Words that start the object of a verb, as in "Updates the total"
+	titleDeterminerRegex = regexp.MustCompile(`(?i)^(?:the|a|an|all|each|every|any|no|some|its|their|this|that|these|those|it|them)$`)
+)
+
+// titleBaseForm returns the base form of a known verb, written either in the
+// third person or in its base form
+func titleBaseForm(word string) (string, bool) {
+	lower := strings.ToLower(word)
+	if base, ok := titleIrregularVerbs[lower]; ok {
+		return base, true
+	}
+
+	candidates := []string{lower, strings.TrimSuffix(lower, "s"), strings.TrimSuffix(lower, "es")}
+	if strings.HasSuffix(lower, "ies") {
+		candidates = append(candidates, strings.TrimSuffix(lower, "ies")+"y")
+	}
+	for _, candidate := range candidates {
+		if slices.Contains(titleKnownVerbs, candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// baseFormFirstWord turns a leading third-person verb into its base form,
+// "Shows the revenue" into "Show the revenue", so that it reads after the
+// prefix. Words that aren't known verbs, as in "Items appear", are left
+// alone, and so are those that read as plural nouns unless the user came
+// before them.
+func baseFormFirstWord(title string, subject bool) string {
+	word, rest, _ := strings.Cut(title, " ")
+	if word != strings.ToLower(word) && word != ucFirst(strings.ToLower(word)) {
+		// An acronym or a name such as "IDs"
+		return title
+	}
+	base, ok := titleBaseForm(word)
+	if !ok {
+		return title
+	}
+	if !subject && !strings.EqualFold(base, word) {
+		next, _, _ := strings.Cut(rest, " ")
+		if titleNounFollowerRegex.MatchString(next) ||
+			(slices.Contains(titleNounVerbs, base) && !titleDeterminerRegex.MatchString(next)) {
+			return title
+		}
+	}
+	return joinTitleWords(base, rest)
+}
+
+// stripTitleSubject leaves out the user a criterion starts with when a known
+// verb follows, "The user clicks Save" reading "clicks Save"
+func stripTitleSubject(title string) (string, bool) {
+	match := titleSubjectRegex.FindStringIndex(title)
+	if match == nil {
+		return title, false
+	}
+	rest := title[match[1]:]
+	word, _, _ := strings.Cut(rest, " ")
+	if _, ok := titleBaseForm(word); !ok {
+		return title, false
+	}
+	return rest, true
+}
+
+func joinTitleWords(word, rest string) string {
+	if rest == "" {
+		return word
+	}
+	return word + " " + rest
+}
+
+// lcFirstWord lowercases the first letter of a title unless its first word
+// is an acronym such as API
+func lcFirstWord(title string) string {
+	word, _, _ := strings.Cut(title, " ")
+	runes := []rune(word)
+	if len(runes) > 1 && unicode.IsUpper(runes[0]) && unicode.IsUpper(runes[1]) {
+		return title
+	}
+	return lcFirst(title)
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTestTitle(t *testing.T) {
+	none, must := "", "must"
+
+	tests := []struct {
+		name     string
+		title    string
+		config   titlesConfig
+		language string
+		expected string
+	}{
+		{"Third person", "Shows the revenue", titlesConfig{}, "", "should show the revenue"},
+		{"Third person spelling", "Applies the discount and matches the total", titlesConfig{}, "", "should apply the discount and matches the total"},
+		{"Known verb before a noun", "Sends emails to members", titlesConfig{}, "", "should send emails to members"},
+		{"Irregular verb", "Is disabled while saving", titlesConfig{}, "", "should be disabled while saving"},
+		{"Plural noun", "Items appear in the list", titlesConfig{}, "", "should items appear in the list"},
+		{"Plural noun like a verb", "Errors are listed", titlesConfig{}, "", "should errors are listed"},
+		{"Plural noun before a copula", "Filters are applied to the list", titlesConfig{}, "", "should filters are applied to the list"},
+		{"Plural noun before an auxiliary", "Updates are saved", titlesConfig{}, "", "should updates are saved"},
+		{"Plural noun before a verb", "Uploads fail with a message", titlesConfig{}, "", "should uploads fail with a message"},
+		{"Plural noun before of", "Types of accounts are listed", titlesConfig{}, "", "should types of accounts are listed"},
+		{"Plural noun like a known verb", "Lists are sorted", titlesConfig{}, "", "should lists are sorted"},
+		{"Verb that doubles as a noun", "Updates the total", titlesConfig{}, "", "should update the total"},
+		{"Subject before a verb that doubles as a noun", "The user uploads files", titlesConfig{}, "", "should upload files"},
+		{"Verb ending in -es", "Focuses the search field", titlesConfig{}, "", "should focus the search field"},
+		{"Verb ending in -zes", "Freezes the header row", titlesConfig{}, "", "should freeze the header row"},
+		{"Verb ending in -yzes", "Analyzes the data", titlesConfig{}, "", "should analyze the data"},
+		{"Subject", `The user clicks "Save"`, titlesConfig{}, "", `should click "Save"`},
+		{"First person subject", "I see the total", titlesConfig{}, "", "should see the total"},
+		{"Subject before a noun", "User settings are saved", titlesConfig{}, "", "should user settings are saved"},
+		{"Duplicate modal", "Should display the total", titlesConfig{}, "", "should display the total"},
+		{"Other modal", "It must reject empty names", titlesConfig{}, "", "should reject empty names"},
+		{"Ability modal", "can delete items", titlesConfig{}, "", "should delete items"},
+		{"Negated modal", "Cannot delete items", titlesConfig{}, "", "should not delete items"},
+		{"Negated third person", "Doesn't show the banner", titlesConfig{}, "", "should not show the banner"},
+		{"Ability", "User can delete items", titlesConfig{}, "", "should allow the user to delete items"},
+		{"Ability with article", "The admin is able to invite members", titlesConfig{}, "", "should allow the admin to invite members"},
+		{"Negated ability", "User can't delete items", titlesConfig{}, "", "should not allow the user to delete items"},
+		{"Negated ability phrase", "A guest is not able to check out", titlesConfig{}, "", "should not allow the guest to check out"},
+		{"Trailing punctuation", "Saves the draft.", titlesConfig{}, "", "should save the draft"},
+		{"Ticket prefix", "[DASH-12] Shows the revenue", titlesConfig{}, "", "should show the revenue"},
+		{"Ticket and AC number", "DASH-12: AC 1.2 - Shows the revenue (#42).", titlesConfig{}, "", "should show the revenue"},
+		{"Acronym", "API errors are shown", titlesConfig{}, "", "should API errors are shown"},
+		{"No prefix", "Shows the revenue.", titlesConfig{Prefix: &none}, "", "Shows the revenue"},
+		{"No prefix keeps modals", "Should display the total", titlesConfig{Prefix: &none}, "", "Should display the total"},
+		{"No prefix keeps abilities", "User can delete items", titlesConfig{Prefix: &none}, "", "User can delete items"},
+		{"Custom prefix", "Must reject empty names", titlesConfig{Prefix: &must}, "", "must reject empty names"},
+		{"Language without prefix", "Zeigt den Umsatz an.", titlesConfig{}, "de", "Zeigt den Umsatz an"},
+		{"Language prefix", "Sollte den Umsatz zeigen", titlesConfig{Prefixes: map[string]string{"de": "sollte"}}, "de-AT", "sollte den Umsatz zeigen"},
+		{"Workspace language", "User can delete items", titlesConfig{Language: "fr"}, "", "User can delete items"},
+		{"English region", "User can delete items", titlesConfig{Language: "en_GB"}, "", "should allow the user to delete items"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := defaultSpecSettings()
+			settings.config.Titles = tt.config
+			settings.language = tt.language
+
+			if result := normalizeTestTitle(tt.title, settings); result != tt.expected {
+				t.Errorf("normalizeTestTitle(%q) = %q, want %q", tt.title, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderAcsTitleLanguage(t *testing.T) {
+	settings := defaultSpecSettings()
+	settings.config.Titles.Prefixes = map[string]string{"de": "sollte"}
+
+	tests := []struct {
+		name     string
+		acs      string
+		expected string
+	}{
+		{
+			name:     "Gherkin header",
+			acs:      "# language: de\nFeature: Umsatz\n  Scenario: Den Umsatz zeigen\n    Then der Umsatz ist sichtbar",
+			expected: "it('sollte den Umsatz zeigen', async () => {",
+		},
+		{
+			name:     "Structured file",
+			acs:      "language: de\ncases:\n  - title: Den Umsatz zeigen",
+			expected: "it('sollte den Umsatz zeigen', async () => {",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document := parseAcText("", tt.acs, settings.config.Acs)
+			if result := renderAcs(document.Nodes, settings.forAcs(document)); !strings.Contains(result, tt.expected) {
+				t.Errorf("renderAcs() does not contain expected phrase %q in:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
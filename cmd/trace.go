@@ -0,0 +1,345 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	traceLinkRegex      = regexp.MustCompile(`^\s*\*?\s*-\s+(\S.*?)\s*$`)
+	traceTitleIDRegex   = regexp.MustCompile(`^\[([^\]\s]+)\]\s+`)
+	traceCommentIDRegex = regexp.MustCompile(`^\s*//\s*(\S+)\s*$`)
+)
+
+// Title of the test every generated spec starts with, which isn't an AC
+const traceBoilerplateTest = "should create"
+
+// Separates the tickets of specs that list several in the report
+const traceSharedSeparator = " + "
+
+// Formats of the traceability report
+const (
+	traceTable    = "table"
+	traceJSON     = "json"
+	traceMarkdown = "markdown"
+)
+
+// traceTest is a test generated from an AC
+type traceTest struct {
+	// Name is the full name of the test, the describe titles and the test
+	// title joined by spaces as test runners report it
+	Name string `json:"name"`
+	ID   string `json:"id,omitempty"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Todo bool   `json:"todo,omitempty"`
+	Skip bool   `json:"skip,omitempty"`
+	// Status is the outcome of the test in the results given
+	Status string `json:"status,omitempty"`
+}
+
+// traceTicket sums up the tests of the specs that list a ticket under "ACs
+// from". Specs listing several tickets don't record which one a test comes
+// from, so their tests are reported once, under the tickets together. The
+// outcomes are counted when test results are given.
+type traceTicket struct {
+	Ticket string `json:"ticket"`
+	// Shared lists the tickets of specs that list more than one
+	Shared   []string    `json:"shared,omitempty"`
+	Criteria int         `json:"criteria"`
+	Todo     int         `json:"todo"`
+	Passed   int         `json:"passed,omitempty"`
+	Failed   int         `json:"failed,omitempty"`
+	Skipped  int         `json:"skipped,omitempty"`
+	NotRun   int         `json:"notRun,omitempty"`
+	Files    []string    `json:"files"`
+	Tests    []traceTest `json:"tests"`
+}
+
+var traceOutput, traceResults string
+
+var traceCmd = &cobra.Command{
+	Use:   "trace [dir]",
+	Short: "Report the ACs tickets of the specs in the workspace and the tests still to implement",
+	Long: `Scan the spec files of the workspace, or of dir, for the tickets listed under
+"ACs from" and report for each ticket how many criteria it has, how many are
+still TODO stubs and which files implement them.
+
+With --results, the tests are matched by name with a Jest or Vitest JSON
+report, or a JUnit XML report, and counted as passed, failed, skipped, TODO
+or not run.`,
+	Example: `
+	ng-spec trace
+	ng-spec trace apps/shop --output markdown > TRACE.md
+	ng-spec trace --results reports/junit.xml
+	`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := traceCommand(cmd.OutOrStdout(), args, traceOutput, traceResults); err != nil {
+			printCommandError(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	traceCmd.Flags().StringVarP(&traceOutput, "output", "o", traceTable, "output format, table, json or markdown")
+	traceCmd.Flags().StringVar(&traceResults, "results", "", "Jest JSON or JUnit XML report of a test run to map onto the ACs")
+
+	rootCmd.AddCommand(traceCmd)
+}
+
+// traceCommand prints the traceability report of the workspace in the
+// working directory, or of the directory given, with the outcomes of the
+// tests when a results file is given
+func traceCommand(w io.Writer, args []string, output, resultsFile string) error {
+	if output != traceTable && output != traceJSON && output != traceMarkdown {
+		return fmt.Errorf("unknown output format %q, use %s, %s or %s", output, traceTable, traceJSON, traceMarkdown)
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		root = args[0]
+	} else if ws, err := findWorkspace(root); err != nil {
+		return err
+	} else if ws != nil {
+		root = ws.root
+	}
+
+	tickets, err := traceSpecs(root)
+	if err != nil {
+		return err
+	}
+
+	withResults := resultsFile != ""
+	if withResults {
+		data, err := os.ReadFile(resultsFile)
+		if err != nil {
+			return err
+		}
+		results, err := parseTestResults(data)
+		if err != nil {
+			return err
+		}
+		applyTestResults(tickets, results)
+	}
+
+	switch output {
+	case traceJSON:
+		if tickets == nil {
+			tickets = []*traceTicket{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(tickets)
+	case traceMarkdown:
+		return writeTraceMarkdown(w, tickets, withResults)
+	}
+	return writeTraceTable(w, tickets, withResults)
+}
+
+// traceSpecs reads the spec files below root and groups their tests by the
+// tickets they list. Specs without a ticket are left out.
+func traceSpecs(root string) ([]*traceTicket, error) {
+	byTicket := map[string]*traceTicket{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if skippedDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".spec.ts") && !strings.HasSuffix(d.Name(), ".test.ts") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		source := string(data)
+		links := specAcsLinks(source)
+		if len(links) == 0 {
+			return nil
+		}
+
+		file, _ := filepath.Rel(root, path)
+		file = filepath.ToSlash(file)
+		tests := specTraceTests(source, file, parseSpecBlocks(source), nil)
+
+		key := strings.Join(links, traceSharedSeparator)
+		ticket := byTicket[key]
+		if ticket == nil {
+			ticket = &traceTicket{Ticket: key}
+			if len(links) > 1 {
+				ticket.Shared = links
+			}
+			byTicket[key] = ticket
+		}
+		ticket.Files = append(ticket.Files, file)
+		ticket.Tests = append(ticket.Tests, tests...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tickets []*traceTicket
+	for _, ticket := range byTicket {
+		ticket.Criteria = len(ticket.Tests)
+		for _, test := range ticket.Tests {
+			if test.Todo {
+				ticket.Todo++
+			}
+		}
+		tickets = append(tickets, ticket)
+	}
+	slices.SortFunc(tickets, func(a, b *traceTicket) int {
+		return strings.Compare(a.Ticket, b.Ticket)
+	})
+
+	return tickets, nil
+}
+
+// specAcsLinks returns the ticket links listed under "ACs from:" in the
+// header of a spec, leaving out the placeholder of specs generated without
+func specAcsLinks(source string) []string {
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		if !strings.Contains(line, "ACs from:") {
+			continue
+		}
+
+		var links []string
+		for _, line := range lines[i+1:] {
+			matches := traceLinkRegex.FindStringSubmatch(line)
+			if matches == nil {
+				break
+			}
+			if link := strings.ReplaceAll(matches[1], `*\/`, "*/"); link != acsLinkPlaceholder {
+				links = append(links, link)
+			}
+		}
+		return links
+	}
+	return nil
+}
+
+// specTraceTests lists the tests of a spec generated from ACs: those with an
+// AC hash, stubs still to implement and tests grouped in a describe inside
+// the component's, as specs generated before AC hashes only have these.
+// The "should create" test and hand-written tests next to it aren't
+// criteria. IDs are read from the title, or from the comment opening the
+// test when IDs are configured to go in comments.
+func specTraceTests(source, file string, blocks []*specBlock, describes []string) []traceTest {
+	var tests []traceTest
+	for _, block := range blocks {
+		if block.isDescribe() {
+			tests = append(tests, specTraceTests(source, file, block.Children, slices.Concat(describes, []string{block.Title}))...)
+			continue
+		}
+		if block.Title == traceBoilerplateTest || (block.Hash == "" && !block.Todo && len(describes) < 2) {
+			continue
+		}
+
+		test := traceTest{
+			Name: strings.Join(slices.Concat(describes, []string{block.Title}), " "),
+			File: file,
+			Line: block.Line,
+			Todo: block.Todo,
+			Skip: block.isSkipped(),
+		}
+		if matches := traceTitleIDRegex.FindStringSubmatch(block.Title); matches != nil {
+			test.ID = matches[1]
+		} else if block.BodyStart >= 0 {
+			body := strings.TrimLeft(source[block.BodyStart+1:block.BodyEnd], " \t\r\n")
+			firstLine, _, _ := strings.Cut(body, "\n")
+			if matches := traceCommentIDRegex.FindStringSubmatch(firstLine); matches != nil {
+				test.ID = matches[1]
+			}
+		}
+		tests = append(tests, test)
+	}
+	return tests
+}
+
+// traceColumns returns the column titles of the report
+func traceColumns(withResults bool) []string {
+	if withResults {
+		return []string{"Ticket", "Criteria", "Passed", "Failed", "TODO", "Skipped", "Not run", "Files"}
+	}
+	return []string{"Ticket", "Criteria", "TODO", "Files"}
+}
+
+// traceRow returns the counts of a ticket in the order of traceColumns,
+// without the files
+func traceRow(ticket *traceTicket, withResults bool) []string {
+	if withResults {
+		return []string{
+			ticket.Ticket,
+			strconv.Itoa(ticket.Criteria),
+			strconv.Itoa(ticket.Passed),
+			strconv.Itoa(ticket.Failed),
+			strconv.Itoa(ticket.Todo),
+			strconv.Itoa(ticket.Skipped),
+			strconv.Itoa(ticket.NotRun),
+		}
+	}
+	return []string{ticket.Ticket, strconv.Itoa(ticket.Criteria), strconv.Itoa(ticket.Todo)}
+}
+
+func writeTraceTable(w io.Writer, tickets []*traceTicket, withResults bool) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, strings.ToUpper(strings.Join(traceColumns(withResults), "\t")))
+	for _, ticket := range tickets {
+		fmt.Fprintln(table, strings.Join(traceRow(ticket, withResults), "\t")+"\t"+strings.Join(ticket.Files, ", "))
+	}
+	return table.Flush()
+}
+
+func writeTraceMarkdown(w io.Writer, tickets []*traceTicket, withResults bool) error {
+	columns := traceColumns(withResults)
+	separators := make([]string, len(columns))
+	for i := range columns {
+		separators[i] = "---:"
+	}
+	separators[0], separators[len(columns)-1] = "---", "---"
+
+	lines := []string{"| " + strings.Join(columns, " | ") + " |", "| " + strings.Join(separators, " | ") + " |"}
+	for _, ticket := range tickets {
+		row := traceRow(ticket, withResults)
+		row[0] = markdownCell(row[0])
+
+		files := make([]string, len(ticket.Files))
+		for i, file := range ticket.Files {
+			files[i] = "`" + file + "`"
+		}
+		lines = append(lines, "| "+strings.Join(append(row, strings.Join(files, "<br>")), " | ")+" |")
+	}
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// markdownCell escapes the pipes of a Markdown table cell
+func markdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const traceSpec = `/**
+ * ACs from:
+ *  - https://example.atlassian.net/browse/DASH-12
+ */
+describe('DashboardComponent', () => {
+	it('should create', async () => {
+		const { view } = await mount();
+		expect(view.fixture.componentInstance).toBeTruthy();
+	});
+
+	describe('Widgets', () => {
+		it('[C1] should show the revenue', async () => {
+			expect(true).toBe(true);
+		}); // ac:1f2e3d4c
+
+		it('should show the orders', async () => {
+			// C2
+			// TODO: Implement test
+		});
+	});
+
+	it('should load the user', async () => {
+		// TODO: Implement test
+	});
+
+	it('should not throw on resize', () => {
+		expect(true).toBe(true);
+	});
+});
+`
+
+func writeTraceFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestTraceSpecs(t *testing.T) {
+	root := writeTraceFiles(t, map[string]string{
+		"src/app/dashboard/dashboard.component.spec.ts": traceSpec,
+		"src/app/user/user.component.spec.ts":           "/**\n * ACs from:\n *  - TODO: Link ACs tickets here\n */\ndescribe('UserComponent', () => {});\n",
+		"node_modules/lib/lib.spec.ts":                  traceSpec,
+	})
+
+	tickets, err := traceSpecs(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*traceTicket{{
+		Ticket:   "https://example.atlassian.net/browse/DASH-12",
+		Criteria: 3,
+		Todo:     2,
+		Files:    []string{"src/app/dashboard/dashboard.component.spec.ts"},
+		Tests: []traceTest{
+			{Name: "DashboardComponent Widgets [C1] should show the revenue", ID: "C1", File: "src/app/dashboard/dashboard.component.spec.ts", Line: 12},
+			{Name: "DashboardComponent Widgets should show the orders", ID: "C2", File: "src/app/dashboard/dashboard.component.spec.ts", Line: 16, Todo: true},
+			{Name: "DashboardComponent should load the user", File: "src/app/dashboard/dashboard.component.spec.ts", Line: 22, Todo: true},
+		},
+	}}
+	if !reflect.DeepEqual(tickets, expected) {
+		t.Errorf("traceSpecs() = %+v, want %+v", tickets[0], expected[0])
+	}
+}
+
+func TestTraceSpecsSharedTickets(t *testing.T) {
+	shared := strings.Replace(traceSpec, " *  - https://example.atlassian.net/browse/DASH-12\n", " *  - https://example.atlassian.net/browse/DASH-12\n *  - DASH-13\n", 1)
+	root := writeTraceFiles(t, map[string]string{
+		"dashboard.component.spec.ts": shared,
+		"orders.component.spec.ts":    traceSpec,
+	})
+
+	tickets, err := traceSpecs(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var summary []string
+	for _, ticket := range tickets {
+		summary = append(summary, fmt.Sprintf("%s: %d %v %q", ticket.Ticket, ticket.Criteria, ticket.Files, ticket.Shared))
+	}
+	expected := []string{
+		`https://example.atlassian.net/browse/DASH-12: 3 [orders.component.spec.ts] []`,
+		`https://example.atlassian.net/browse/DASH-12 + DASH-13: 3 [dashboard.component.spec.ts] ["https://example.atlassian.net/browse/DASH-12" "DASH-13"]`,
+	}
+	if !reflect.DeepEqual(summary, expected) {
+		t.Errorf("traceSpecs() = %q, want %q", summary, expected)
+	}
+}
+
+func TestSpecAcsLinks(t *testing.T) {
+	source := "/**\n * ACs from:\n *  - DASH-1\n *  - https://example.com/*\\/DASH-2\n */\ndescribe('A', () => {});"
+	if links := specAcsLinks(source); !reflect.DeepEqual(links, []string{"DASH-1", "https://example.com/*/DASH-2"}) {
+		t.Errorf("specAcsLinks() = %q", links)
+	}
+}
+
+func TestTraceCommand(t *testing.T) {
+	root := writeTraceFiles(t, map[string]string{"dashboard.component.spec.ts": traceSpec})
+
+	tests := []struct {
+		output   string
+		expected []string
+	}{
+		{
+			output:   traceTable,
+			expected: []string{"TICKET", "https://example.atlassian.net/browse/DASH-12  3         2     dashboard.component.spec.ts"},
+		},
+		{
+			output:   traceJSON,
+			expected: []string{`"criteria": 3,`, `"id": "C1",`},
+		},
+		{
+			output:   traceMarkdown,
+			expected: []string{"| https://example.atlassian.net/browse/DASH-12 | 3 | 2 | `dashboard.component.spec.ts` |"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := traceCommand(&out, []string{root}, tt.output, ""); err != nil {
+				t.Fatal(err)
+			}
+			for _, phrase := range tt.expected {
+				if !strings.Contains(out.String(), phrase) {
+					t.Errorf("traceCommand() does not contain expected phrase %q in:\n%s", phrase, out.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Supported issue trackers
+const (
+	trackerJira   = "jira"
+	trackerGitHub = "github"
+	trackerAzure  = "azure"
+)
+
+// Environment variables holding the tracker token when none is configured
+var trackerTokenEnv = map[string]string{
+	trackerJira:   "JIRA_API_TOKEN",
+	trackerGitHub: "GITHUB_TOKEN",
+	trackerAzure:  "AZURE_DEVOPS_TOKEN",
+}
+
+// Jira REST API versions, in the order they're tried. Jira Server and Data
+// Center only have version 2.
+var jiraAPIVersions = []string{"3", "2"}
+
+// Azure DevOps field holding the ACs of a work item by default
+const azureAcsField = "Microsoft.VSTS.Common.AcceptanceCriteria"
+
+var trackerClient = &http.Client{Timeout: 15 * time.Second}
+
+// fetchTicket downloads the ACs of a ticket from the configured tracker and
+// parses them like a file of the same format
+func fetchTicket(ticket string, config acsConfig) (acDocument, error) {
+	tracker := config.Tracker
+	if tracker.Type == "" || tracker.URL == "" {
+		return acDocument{}, fmt.Errorf("no issue tracker configured in %s", configFileName)
+	}
+
+	token := os.Getenv(tracker.tokenEnv())
+	base := strings.TrimSuffix(tracker.URL, "/")
+
+	switch tracker.Type {
+	case trackerJira:
+		field := config.Jira.Field
+		if field == "" {
+			field = "description"
+		}
+
+		versions := jiraAPIVersions
+		if config.Jira.APIVersion != "" {
+			versions = []string{config.Jira.APIVersion}
+		}
+
+		var body []byte
+		var err error
+		for _, version := range versions {
+			var request *http.Request
+			request, err = http.NewRequest(http.MethodGet, base+"/rest/api/"+version+"/issue/"+url.PathEscape(ticket)+"?fields="+url.QueryEscape(field), nil)
+			if err != nil {
+				return acDocument{}, err
+			}
+			if tracker.User != "" {
+				request.SetBasicAuth(tracker.User, token)
+			} else if token != "" {
+				request.Header.Set("Authorization", "Bearer "+token)
+			}
+
+			// A missing API is a 404 too, so the next version is tried
+			body, err = trackerRequest(request)
+			var status trackerStatusError
+			if !errors.As(err, &status) || status.code != http.StatusNotFound {
+				break
+			}
+		}
+		if err != nil {
+			return acDocument{}, err
+		}
+		return parseJiraIssue(string(body), config), nil
+
+	case trackerGitHub:
+		repo, number := tracker.Repo, strings.TrimPrefix(ticket, "#")
+		if before, after, found := strings.Cut(number, "#"); found {
+			repo, number = before, after
+		}
+		if repo == "" {
+			return acDocument{}, fmt.Errorf("no GitHub repository for ticket %s, use owner/repo#%s or set repo in %s", ticket, number, configFileName)
+		}
+
+		request, err := http.NewRequest(http.MethodGet, base+"/repos/"+repo+"/issues/"+url.PathEscape(number), nil)
+		if err != nil {
+			return acDocument{}, err
+		}
+		request.Header.Set("Accept", "application/vnd.github+json")
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		body, err := trackerRequest(request)
+		if err != nil {
+			return acDocument{}, err
+		}
+
+		var issue struct {
+			Body    string `json:"body"`
+			HTMLURL string `json:"html_url"`
+		}
+		if err := json.Unmarshal(body, &issue); err != nil {
+			return acDocument{}, fmt.Errorf("invalid GitHub issue: %w", err)
+		}
+
+		document := parseAcText(".md", issue.Body, config)
+		document.Link = issue.HTMLURL
+		return document, nil
+
+	case trackerAzure:
+		field := tracker.Field
+		if field == "" {
+			field = azureAcsField
+		}
+
+		request, err := http.NewRequest(http.MethodGet, base+"/_apis/wit/workitems/"+url.PathEscape(ticket)+"?api-version=7.0", nil)
+		if err != nil {
+			return acDocument{}, err
+		}
+		if token != "" {
+			request.SetBasicAuth("", token)
+		}
+
+		body, err := trackerRequest(request)
+		if err != nil {
+			return acDocument{}, err
+		}
+
+		var item struct {
+			Fields map[string]any `json:"fields"`
+			Links  struct {
+				HTML struct {
+					Href string `json:"href"`
+				} `json:"html"`
+			} `json:"_links"`
+		}
+		if err := json.Unmarshal(body, &item); err != nil {
+			return acDocument{}, fmt.Errorf("invalid Azure DevOps work item: %w", err)
+		}
+
+		value, _ := item.Fields[field].(string)
+		if value == "" {
+			return acDocument{}, fmt.Errorf("work item %s has no %s field", ticket, field)
+		}
+
+		document := parseHTML(value, config)
+		document.Link = item.Links.HTML.Href
+		return document, nil
+	}
+
+	return acDocument{}, fmt.Errorf("unknown issue tracker %q", tracker.Type)
+}
+
+// trackerRequest sends a tracker API request and returns the response body
+func trackerRequest(request *http.Request) ([]byte, error) {
+	response, err := trackerClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, trackerStatusError{request.URL.Host, response.Status, response.StatusCode}
+	}
+	return body, nil
+}
+
+// trackerStatusError is a tracker response other than 200 OK
+type trackerStatusError struct {
+	host   string
+	status string
+	code   int
+}
+
+func (e trackerStatusError) Error() string {
+	return fmt.Sprintf("%s responded with %s", e.host, e.status)
+}
+
+// tokenEnv returns the environment variable holding the tracker token
+func (t trackerConfig) tokenEnv() string {
+	if t.TokenEnv != "" {
+		return t.TokenEnv
+	}
+	return trackerTokenEnv[t.Type]
+}
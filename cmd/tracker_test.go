@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchTicket(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, basic := r.BasicAuth()
+		authorized := r.Header.Get("Authorization") == "Bearer secret" || (basic && password == "secret")
+
+		switch {
+		case !authorized:
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/rest/api/3/issue/DASH-1" && r.URL.Query().Get("fields") == "customfield_10042" && user == "qa@example.com":
+			w.Write([]byte(`{"key": "DASH-1", "self": "https://example.atlassian.net/rest/api/3/issue/1", "fields": {"customfield_10042": "# Revenue\n# Orders"}}`))
+		case r.URL.Path == "/jira/rest/api/2/issue/DASH-2":
+			w.Write([]byte(`{"key": "DASH-2", "self": "https://jira.example.com/rest/api/2/issue/2", "fields": {"description": "# Revenue"}}`))
+		case r.URL.Path == "/repos/acme/shop/issues/7":
+			w.Write([]byte(`{"html_url": "https://github.com/acme/shop/issues/7", "body": "## Revenue\n- Shows the total\n- Shows the currency"}`))
+		case r.URL.Path == "/acme/shop/_apis/wit/workitems/42":
+			w.Write([]byte(`{"fields": {"Microsoft.VSTS.Common.AcceptanceCriteria": "<ol><li>Revenue &amp; costs</li><li>Orders</li></ol>"}, "_links": {"html": {"href": "https://dev.azure.com/acme/shop/_workitems/edit/42"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("JIRA_API_TOKEN", "secret")
+	t.Setenv("GITHUB_TOKEN", "secret")
+	t.Setenv("AZURE_DEVOPS_TOKEN", "secret")
+
+	tests := []struct {
+		name     string
+		ticket   string
+		config   acsConfig
+		expected string
+		link     string
+	}{
+		{
+			name:     "Jira custom field",
+			ticket:   "DASH-1",
+			config:   acsConfig{Tracker: trackerConfig{Type: trackerJira, URL: server.URL, User: "qa@example.com"}, Jira: jiraConfig{Field: "customfield_10042"}},
+			expected: "Revenue Orders",
+			link:     "https://example.atlassian.net/browse/DASH-1",
+		},
+		{
+			name:     "Jira Server",
+			ticket:   "DASH-2",
+			config:   acsConfig{Tracker: trackerConfig{Type: trackerJira, URL: server.URL + "/jira"}},
+			expected: "Revenue",
+			link:     "https://jira.example.com/browse/DASH-2",
+		},
+		{
+			name:     "Jira API version",
+			ticket:   "DASH-2",
+			config:   acsConfig{Tracker: trackerConfig{Type: trackerJira, URL: server.URL + "/jira"}, Jira: jiraConfig{APIVersion: "2"}},
+			expected: "Revenue",
+			link:     "https://jira.example.com/browse/DASH-2",
+		},
+		{
+			name:     "GitHub issue in another repository",
+			ticket:   "acme/shop#7",
+			config:   acsConfig{Tracker: trackerConfig{Type: trackerGitHub, URL: server.URL, Repo: "acme/web"}},
+			expected: "Revenue(Shows the total Shows the currency)",
+			link:     "https://github.com/acme/shop/issues/7",
+		},
+		{
+			name:     "Azure DevOps work item",
+			ticket:   "42",
+			config:   acsConfig{Tracker: trackerConfig{Type: trackerAzure, URL: server.URL + "/acme/shop/"}},
+			expected: "Revenue & costs Orders",
+			link:     "https://dev.azure.com/acme/shop/_workitems/edit/42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := fetchTicket(tt.ticket, tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result := outlineShape(document.Nodes); result != tt.expected {
+				t.Errorf("fetchTicket() = %q, want %q", result, tt.expected)
+			}
+			if document.Link != tt.link {
+				t.Errorf("fetchTicket() link = %q, want %q", document.Link, tt.link)
+			}
+		})
+	}
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "")
+		config := acsConfig{Tracker: trackerConfig{Type: trackerGitHub, URL: server.URL, Repo: "acme/shop"}}
+		if _, err := fetchTicket("7", config); err == nil {
+			t.Error("fetchTicket() should fail without a token")
+		}
+	})
+
+	t.Run("Falls back to the form", func(t *testing.T) {
+		settings := defaultSpecSettings()
+		settings.config.Acs.Tracker = trackerConfig{Type: trackerJira, URL: server.URL}
+
+		link, document, err := readAcs(generateOptions{ticket: "DASH-404"}, settings, mockUserInput{acsText: "1. Typed in the form"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if outlineShape(document.Nodes) != "Typed in the form" || link != "" {
+			t.Errorf("readAcs() = %q, %q, want the ACs from the form", link, outlineShape(document.Nodes))
+		}
+	})
+}
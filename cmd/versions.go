@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Packages the generated spec depends on
+const (
+	angularCorePackage    = "@angular/core"
+	angularCdkPackage     = "@angular/cdk"
+	testingLibraryPackage = "@testing-library/angular"
+	ngrxStorePackage      = "@ngrx/store"
+	userEventPackage      = "@testing-library/user-event"
+)
+
+var trackedPackages = []string{
+	angularCorePackage,
+	angularCdkPackage,
+	testingLibraryPackage,
+	ngrxStorePackage,
+	userEventPackage,
+}
+
+// packageVersions maps package names to their resolved version, taken from
+// the lockfile when present and from the package.json range otherwise
+type packageVersions map[string]string
+
+type packageManifest struct {
+	Dependencies     map[string]string `json:"dependencies"`
+	DevDependencies  map[string]string `json:"devDependencies"`
+	PeerDependencies map[string]string `json:"peerDependencies"`
+}
+
+// findPackageVersions walks up from dir and resolves the versions of the
+// tracked packages from the package.json files on the way, the nearest one
+// first. Libraries of a workspace often declare only some packages, as peer
+// dependencies, so the walk goes on up to the workspace root, or without one
+// up to the directory holding the lockfile or the git repository. Locked
+// versions are read from where the walk stops. When the walk reaches the
+// filesystem root instead, only the nearest package.json is kept, the
+// project's own, as those above it can belong to anything. It returns nil
+// when no package.json is found.
+func findPackageVersions(dir, root string) (packageVersions, error) {
+	var versions, nearest packageVersions
+
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+		if err == nil {
+			if versions == nil {
+				versions = packageVersions{}
+			}
+			if err := readPackageVersions(versions, data); err != nil {
+				return nil, err
+			}
+			if nearest == nil {
+				nearest = maps.Clone(versions)
+			}
+		}
+
+		if dir == root || (root == "" && (hasLockfile(dir) || isGitRoot(dir))) {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nearest, nil
+		}
+		dir = parent
+	}
+
+	if versions == nil {
+		return nil, nil
+	}
+	locked := readLockfileVersions(dir)
+	for name := range versions {
+		if version, ok := locked[name]; ok {
+			versions[name] = version
+		}
+	}
+	return versions, nil
+}
+
+// readPackageVersions adds the tracked packages a package.json declares and
+// versions doesn't have yet
+func readPackageVersions(versions packageVersions, data []byte) error {
+	var manifest packageManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("invalid package.json: %w", err)
+	}
+
+	for _, name := range trackedPackages {
+		if versions.has(name) {
+			continue
+		}
+		for _, dependencies := range []map[string]string{manifest.Dependencies, manifest.DevDependencies, manifest.PeerDependencies} {
+			if version, ok := dependencies[name]; ok {
+				versions[name] = version
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Lockfiles in the order they're read
+var lockfileNames = []string{"package-lock.json", "yarn.lock", "pnpm-lock.yaml"}
+
+func hasLockfile(dir string) bool {
+	for _, name := range lockfileNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isGitRoot reports whether dir is the root of a git repository or worktree
+func isGitRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// readLockfileVersions reads installed versions from package-lock.json,
+// yarn.lock or pnpm-lock.yaml, whichever is found first
+func readLockfileVersions(dir string) map[string]string {
+	readers := []func([]byte) map[string]string{readNpmLockfile, readYarnLockfile, readPnpmLockfile}
+	for i, name := range lockfileNames {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			return readers[i](data)
+		}
+	}
+	return nil
+}
+
+func readNpmLockfile(data []byte) map[string]string {
+	type lockedPackage struct {
+		Version string `json:"version"`
+	}
+
+	var lockfile struct {
+		Packages     map[string]lockedPackage `json:"packages"`
+		Dependencies map[string]lockedPackage `json:"dependencies"`
+	}
+
+	if err := json.Unmarshal(data, &lockfile); err != nil {
+		return nil
+	}
+
+	versions := map[string]string{}
+	for _, name := range trackedPackages {
+		if pkg, ok := lockfile.Packages["node_modules/"+name]; ok && pkg.Version != "" {
+			versions[name] = pkg.Version
+		} else if pkg, ok := lockfile.Dependencies[name]; ok && pkg.Version != "" {
+			versions[name] = pkg.Version
+		}
+	}
+
+	return versions
+}
+
+func readYarnLockfile(data []byte) map[string]string {
+	versions := map[string]string{}
+	var current string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "#") {
+			current = ""
+			for _, name := range trackedPackages {
+				header := strings.TrimLeft(line, `"`)
+				if strings.HasPrefix(header, name+"@") {
+					current = name
+				}
+			}
+			continue
+		}
+
+		if current == "" {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if version, ok := strings.CutPrefix(trimmed, "version"); ok {
+			version = strings.Trim(strings.TrimSpace(strings.TrimPrefix(version, ":")), `"`)
+			if _, seen := versions[current]; !seen {
+				versions[current] = version
+			}
+			current = ""
+		}
+	}
+
+	return versions
+}
+
+func readPnpmLockfile(data []byte) map[string]string {
+	versions := map[string]string{}
+
+	for _, name := range trackedPackages {
+		pattern := regexp.MustCompile(`(?m)^\s+['"]?/?` + regexp.QuoteMeta(name) + `@(\d[^:('"\s]*)`)
+		if matches := pattern.FindSubmatch(data); matches != nil {
+			versions[name] = string(matches[1])
+		}
+	}
+
+	return versions
+}
+
+var majorVersionRegex = regexp.MustCompile(`\d+`)
+
+// major returns the major version of a package, or 0 when it is unknown
+func (v packageVersions) major(name string) int {
+	version, ok := v[name]
+	if !ok {
+		return 0
+	}
+
+	// Skip protocols such as npm:@angular/core@17 down to the version itself
+	if i := strings.LastIndex(version, "@"); i >= 0 {
+		version = version[i+1:]
+	}
+
+	match := majorVersionRegex.FindString(version)
+	if match == "" {
+		return 0
+	}
+
+	major, _ := strconv.Atoi(match)
+	return major
+}
+
+func (v packageVersions) has(name string) bool {
+	_, ok := v[name]
+	return ok
+}
+
+// missingPackages lists the packages the generated spec imports that aren't
+// installed in the project, user-event only when snippets may use it
+func (v packageVersions) missingPackages(snippets snippetsConfig) []string {
+	if v == nil {
+		return nil
+	}
+
+	names := []string{angularCorePackage, testingLibraryPackage, angularCdkPackage}
+	if snippets.usesUserEvent() {
+		names = append(names, userEventPackage)
+	}
+
+	var missing []string
+	for _, name := range names {
+		if !v.has(name) {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing
+}
+
+// applyTo adjusts the settings to the APIs available in the installed
+// package versions. Explicit schematic options always take precedence.
+func (v packageVersions) applyTo(settings specSettings, schematic componentSchematic) specSettings {
+	if v == nil {
+		return settings
+	}
+
+	angular := v.major(angularCorePackage)
+
+	if angular > 0 && angular < 15 {
+		settings.httpTestingModule = true
+	}
+
+	// Angular 20 dropped the type suffix from generated component names
+	if schematic.Type == nil && angular >= 20 {
+		settings.componentType = ""
+	}
+
+	// Standalone became the component schematic default in Angular 17
+	if schematic.Standalone == nil && angular > 0 && angular < 17 {
+		settings.standalone = false
+	}
+
+	// Testing Library renamed componentProperties to componentInputs in 13
+	// and to inputs in 17
+	switch testingLibrary := v.major(testingLibraryPackage); {
+	case testingLibrary == 0 || testingLibrary >= 17:
+		settings.inputsOption = "inputs"
+	case testingLibrary >= 13:
+		settings.inputsOption = "componentInputs"
+	default:
+		settings.inputsOption = "componentProperties"
+	}
+
+	settings.mockStore = v.has(ngrxStorePackage)
+
+	return settings
+}
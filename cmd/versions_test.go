@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPackageVersionsMajor(t *testing.T) {
+	versions := packageVersions{
+		angularCorePackage:    "^17.3.0",
+		testingLibraryPackage: "~14.1",
+		ngrxStorePackage:      "latest",
+		angularCdkPackage:     "npm:@angular/cdk@16.2.1",
+	}
+
+	tests := []struct {
+		name     string
+		pkg      string
+		expected int
+	}{
+		{"Caret range", angularCorePackage, 17},
+		{"Tilde range", testingLibraryPackage, 14},
+		{"Dist tag", ngrxStorePackage, 0},
+		{"Aliased package", angularCdkPackage, 16},
+		{"Missing package", "@angular/material", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := versions.major(tt.pkg); result != tt.expected {
+				t.Errorf("major(%q) = %d, want %d", tt.pkg, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindPackageVersionsWithLockfiles(t *testing.T) {
+	manifest := `{
+		"dependencies": {"@angular/core": "^14.0.0", "@ngrx/store": "^14.0.0"},
+		"devDependencies": {"@testing-library/angular": "^12.0.0"}
+	}`
+
+	tests := []struct {
+		name     string
+		lockfile map[string]string
+		expected packageVersions
+	}{
+		{
+			name:     "No lockfile",
+			lockfile: map[string]string{},
+			expected: packageVersions{
+				angularCorePackage:    "^14.0.0",
+				ngrxStorePackage:      "^14.0.0",
+				testingLibraryPackage: "^12.0.0",
+			},
+		},
+		{
+			name: "npm lockfile",
+			lockfile: map[string]string{
+				"package-lock.json": `{"packages": {"node_modules/@angular/core": {"version": "14.2.12"}}}`,
+			},
+			expected: packageVersions{
+				angularCorePackage:    "14.2.12",
+				ngrxStorePackage:      "^14.0.0",
+				testingLibraryPackage: "^12.0.0",
+			},
+		},
+		{
+			name: "Yarn lockfile",
+			lockfile: map[string]string{
+				"yarn.lock": "\"@angular/core@^14.0.0\":\n  version \"14.3.0\"\n\n\"@ngrx/store@^14.0.0\", \"@ngrx/store@^14.1.0\":\n  version \"14.3.2\"\n",
+			},
+			expected: packageVersions{
+				angularCorePackage:    "14.3.0",
+				ngrxStorePackage:      "14.3.2",
+				testingLibraryPackage: "^12.0.0",
+			},
+		},
+		{
+			name: "pnpm lockfile",
+			lockfile: map[string]string{
+				"pnpm-lock.yaml": "packages:\n\n  /@testing-library/angular@12.1.2(@angular/core@14.3.0):\n    resolution: {}\n",
+			},
+			expected: packageVersions{
+				angularCorePackage:    "^14.0.0",
+				ngrxStorePackage:      "^14.0.0",
+				testingLibraryPackage: "12.1.2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir, err := os.MkdirTemp("", "ng-spec-versions")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(tempDir)
+
+			files := map[string]string{"package.json": manifest}
+			for name, content := range tt.lockfile {
+				files[name] = content
+			}
+			writeFiles(t, tempDir, files)
+
+			versions, err := findPackageVersions(tempDir, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(versions, tt.expected) {
+				t.Errorf("findPackageVersions() = %v, want %v", versions, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindPackageVersionsOutsideWorkspace(t *testing.T) {
+	parent := t.TempDir()
+	writeFiles(t, parent, map[string]string{
+		"package.json": `{"dependencies": {"@angular/core": "^14.0.0"}}`,
+	})
+	dir := filepath.Join(parent, "project", "src")
+	writeFiles(t, filepath.Join(parent, "project"), map[string]string{
+		"package.json": `{"dependencies": {"@testing-library/angular": "^12.0.0"}}`,
+	})
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a lockfile or a repository only the project's own package.json
+	// is read, not the one above it
+	versions, err := findPackageVersions(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := packageVersions{testingLibraryPackage: "^12.0.0"}
+	if !reflect.DeepEqual(versions, expected) {
+		t.Errorf("findPackageVersions() outside a workspace and a repository = %v, want %v", versions, expected)
+	}
+
+	writeFiles(t, parent, map[string]string{".git/HEAD": "ref: refs/heads/main\n"})
+	versions, err = findPackageVersions(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = packageVersions{angularCorePackage: "^14.0.0", testingLibraryPackage: "^12.0.0"}
+	if !reflect.DeepEqual(versions, expected) {
+		t.Errorf("findPackageVersions() in a repository = %v, want %v", versions, expected)
+	}
+}
+
+func TestFindPackageVersionsInWorkspaceLibrary(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"nx.json":              `{}`,
+		"package.json":         `{"dependencies": {"@angular/core": "^14.0.0", "@angular/cdk": "^14.0.0", "@ngrx/store": "^14.0.0"}, "devDependencies": {"@testing-library/angular": "^12.0.0"}}`,
+		"package-lock.json":    `{"packages": {"node_modules/@angular/core": {"version": "14.2.12"}}}`,
+		"libs/ui/package.json": `{"name": "@acme/ui", "peerDependencies": {"@angular/core": ">=14.0.0", "@angular/cdk": ">=14.0.0"}}`,
+	})
+	dir := filepath.Join(root, "libs", "ui", "src", "lib")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := findPackageVersions(dir, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := packageVersions{
+		angularCorePackage:    "14.2.12",
+		angularCdkPackage:     ">=14.0.0",
+		ngrxStorePackage:      "^14.0.0",
+		testingLibraryPackage: "^12.0.0",
+	}
+	if !reflect.DeepEqual(versions, expected) {
+		t.Errorf("findPackageVersions() = %v, want %v", versions, expected)
+	}
+
+	settings := versions.applyTo(defaultSpecSettings(), componentSchematic{})
+	if !settings.httpTestingModule || !settings.mockStore {
+		t.Errorf("applyTo() = %+v, want HttpClientTestingModule and the mock store", settings)
+	}
+	if missing := versions.missingPackages(snippetsConfig{DisableDefaults: true}); len(missing) > 0 {
+		t.Errorf("missingPackages() = %v, want none", missing)
+	}
+}
+
+func TestPackageVersionsApplyTo(t *testing.T) {
+	legacy := packageVersions{
+		angularCorePackage:    "14.2.0",
+		testingLibraryPackage: "12.1.0",
+		angularCdkPackage:     "14.2.0",
+	}
+
+	settings := legacy.applyTo(defaultSpecSettings(), componentSchematic{})
+	if !settings.httpTestingModule || settings.standalone || settings.mockStore {
+		t.Errorf("applyTo() for Angular 14 = %+v, want HttpClientTestingModule, no standalone and no mock store", settings)
+	}
+	if settings.inputsOption != "componentProperties" {
+		t.Errorf("applyTo() inputsOption = %q, want componentProperties", settings.inputsOption)
+	}
+
+	modern := packageVersions{angularCorePackage: "^20.0.0", testingLibraryPackage: "^18.0.0"}
+	settings = modern.applyTo(defaultSpecSettings(), componentSchematic{})
+	if settings.componentType != "" || settings.inputsOption != "inputs" {
+		t.Errorf("applyTo() for Angular 20 = %+v, want no type suffix and inputs", settings)
+	}
+
+	componentType := "component"
+	settings = modern.applyTo(defaultSpecSettings(), componentSchematic{Type: &componentType})
+	if settings.componentType != "component" {
+		t.Errorf("applyTo() should keep the schematic type, got %q", settings.componentType)
+	}
+
+	if missing := modern.missingPackages(snippetsConfig{}); !reflect.DeepEqual(missing, []string{angularCdkPackage, userEventPackage}) {
+		t.Errorf("missingPackages() = %v, want [%s %s]", missing, angularCdkPackage, userEventPackage)
+	}
+	if missing := modern.missingPackages(snippetsConfig{DisableDefaults: true}); !reflect.DeepEqual(missing, []string{angularCdkPackage}) {
+		t.Errorf("missingPackages() without snippets = %v, want [%s]", missing, angularCdkPackage)
+	}
+	if missing := (packageVersions{testingLibraryPackage: "^18.0.0", angularCdkPackage: "^20.0.0"}).missingPackages(snippetsConfig{DisableDefaults: true}); !reflect.DeepEqual(missing, []string{angularCorePackage}) {
+		t.Errorf("missingPackages() without Angular = %v, want [%s]", missing, angularCorePackage)
+	}
+}
+
+func TestCreateTemplateLegacyAngular(t *testing.T) {
+	settings := defaultSpecSettings()
+	settings.httpTestingModule = true
+	settings.mockStore = false
+	settings.inputsOption = "componentInputs"
+
+	result := createTemplate("user", settings)
+
+	expectedPhrases := []string{
+		"import { HttpClientTestingModule, HttpTestingController } from '@angular/common/http/testing';",
+		"imports: [HttpClientTestingModule],",
+		"componentInputs: {}, // TODO: Set the component's inputs",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("createTemplate() does not contain expected phrase: %q", phrase)
+		}
+	}
+
+	for _, phrase := range []string{"provideHttpClient", "provideMockStore"} {
+		if strings.Contains(result, phrase) {
+			t.Errorf("createTemplate() contains unexpected phrase: %q", phrase)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// testFramework identifies the test runner a project is configured with
+type testFramework string
+
+const (
+	frameworkUnknown       testFramework = ""
+	frameworkKarma         testFramework = "karma"
+	frameworkJest          testFramework = "jest"
+	frameworkWebTestRunner testFramework = "web-test-runner"
+	frameworkVitest        testFramework = "vitest"
+)
+
+// usesJasmine reports whether the framework runs specs with Jasmine globals
+func (f testFramework) usesJasmine() bool {
+	return f == frameworkKarma || f == frameworkWebTestRunner
+}
+
+// componentSchematic holds the component schematic defaults that affect the spec
+type componentSchematic struct {
+	SkipTests  *bool   `json:"skipTests"`
+	Standalone *bool   `json:"standalone"`
+	Type       *string `json:"type"`
+	Prefix     string  `json:"prefix"`
+}
+
+// merge returns s with every unset field taken from fallback
+func (s componentSchematic) merge(fallback componentSchematic) componentSchematic {
+	if s.SkipTests == nil {
+		s.SkipTests = fallback.SkipTests
+	}
+	if s.Standalone == nil {
+		s.Standalone = fallback.Standalone
+	}
+	if s.Type == nil {
+		s.Type = fallback.Type
+	}
+	if s.Prefix == "" {
+		s.Prefix = fallback.Prefix
+	}
+	return s
+}
+
+type workspaceProject struct {
+	name       string
+	root       string // absolute
+	sourceRoot string // absolute
+	prefix     string
+	schematic  componentSchematic
+	framework  testFramework
+}
+
+type workspace struct {
+	root     string
+	projects []*workspaceProject
+}
+
+// Schematic collections that generate Angular components, in lookup order
+var componentSchematicKeys = []string{
+	"@schematics/angular:component",
+	"@nx/angular:component",
+	"@nrwl/angular:component",
+}
+
+type targetConfig struct {
+	Builder  string `json:"builder"`
+	Executor string `json:"executor"`
+	Options  struct {
+		Runner string `json:"runner"`
+	} `json:"options"`
+}
+
+type projectConfig struct {
+	Name       string                     `json:"name"`
+	Root       *string                    `json:"root"`
+	SourceRoot string                     `json:"sourceRoot"`
+	Prefix     string                     `json:"prefix"`
+	Schematics map[string]json.RawMessage `json:"schematics"`
+	Generators map[string]json.RawMessage `json:"generators"`
+	Architect  map[string]targetConfig    `json:"architect"`
+	Targets    map[string]targetConfig    `json:"targets"`
+}
+
+type angularConfig struct {
+	Projects   map[string]projectConfig   `json:"projects"`
+	Schematics map[string]json.RawMessage `json:"schematics"`
+}
+
+type nxConfig struct {
+	Generators map[string]json.RawMessage `json:"generators"`
+}
+
+// Directories never searched for Nx project.json files or components
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	".git":         true,
+	".angular":     true,
+	".nx":          true,
+	"dist":         true,
+	"coverage":     true,
+	"tmp":          true,
+}
+
+// findWorkspace walks up from dir looking for angular.json or nx.json and
+// loads the projects it describes. It returns nil when dir is not inside an
+// Angular or Nx workspace.
+func findWorkspace(dir string) (*workspace, error) {
+	for {
+		_, angularErr := os.Stat(filepath.Join(dir, "angular.json"))
+		_, nxErr := os.Stat(filepath.Join(dir, "nx.json"))
+
+		if angularErr == nil || nxErr == nil {
+			return loadWorkspace(dir)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+func loadWorkspace(root string) (*workspace, error) {
+	ws := &workspace{root: root}
+	var defaults componentSchematic
+
+	if data, err := os.ReadFile(filepath.Join(root, "angular.json")); err == nil {
+		var config angularConfig
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("invalid angular.json: %w", err)
+		}
+
+		defaults = readComponentSchematic(config.Schematics)
+
+		for name, project := range config.Projects {
+			ws.projects = append(ws.projects, newWorkspaceProject(root, name, "", project))
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(root, "nx.json")); err == nil {
+		var config nxConfig
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("invalid nx.json: %w", err)
+		}
+
+		defaults = defaults.merge(readComponentSchematic(config.Generators))
+
+		projects, err := findNxProjects(root)
+		if err != nil {
+			return nil, err
+		}
+		ws.projects = append(ws.projects, projects...)
+	}
+
+	for _, project := range ws.projects {
+		project.schematic = project.schematic.merge(defaults)
+		if project.prefix == "" {
+			project.prefix = project.schematic.Prefix
+		}
+	}
+
+	// Deepest roots first, so nested projects win over the workspace root project
+	sort.SliceStable(ws.projects, func(i, j int) bool {
+		return len(ws.projects[i].root) > len(ws.projects[j].root)
+	})
+
+	return ws, nil
+}
+
+func findNxProjects(root string) ([]*workspaceProject, error) {
+	var projects []*workspaceProject
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if skippedDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.Name() != "project.json" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var config projectConfig
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("invalid %s: %w", path, err)
+		}
+
+		dir, _ := filepath.Rel(root, filepath.Dir(path))
+		name := config.Name
+		if name == "" {
+			name = filepath.Base(filepath.Dir(path))
+		}
+
+		projects = append(projects, newWorkspaceProject(root, name, dir, config))
+		return nil
+	})
+
+	return projects, err
+}
+
+func newWorkspaceProject(workspaceRoot, name, dir string, config projectConfig) *workspaceProject {
+	root := dir
+	if config.Root != nil {
+		root = *config.Root
+	}
+
+	project := &workspaceProject{
+		name:   name,
+		root:   filepath.Join(workspaceRoot, root),
+		prefix: config.Prefix,
+	}
+
+	project.sourceRoot = project.root
+	if config.SourceRoot != "" {
+		project.sourceRoot = filepath.Join(workspaceRoot, config.SourceRoot)
+	}
+
+	project.schematic = readComponentSchematic(config.Schematics).
+		merge(readComponentSchematic(config.Generators))
+
+	targets := config.Targets
+	if config.Architect != nil {
+		targets = config.Architect
+	}
+
+	if test, ok := targets["test"]; ok {
+		project.framework = detectTestFramework(test)
+	}
+
+	return project
+}
+
+func readComponentSchematic(schematics map[string]json.RawMessage) componentSchematic {
+	var result componentSchematic
+
+	for _, key := range componentSchematicKeys {
+		raw, ok := schematics[key]
+		if !ok {
+			continue
+		}
+
+		var schematic componentSchematic
+		if err := json.Unmarshal(raw, &schematic); err == nil {
+			result = result.merge(schematic)
+		}
+	}
+
+	return result
+}
+
+// detectTestFramework maps a test target's builder or executor to a framework
+func detectTestFramework(target targetConfig) testFramework {
+	builder := target.Builder
+	if builder == "" {
+		builder = target.Executor
+	}
+
+	switch {
+	case builder == "@angular/build:unit-test":
+		if target.Options.Runner == "karma" {
+			return frameworkKarma
+		}
+		return frameworkVitest
+	case strings.HasSuffix(builder, ":karma"):
+		return frameworkKarma
+	case strings.HasSuffix(builder, ":web-test-runner"):
+		return frameworkWebTestRunner
+	case strings.Contains(builder, "jest"):
+		return frameworkJest
+	case strings.Contains(builder, "vitest"), strings.Contains(builder, "vite:test"):
+		return frameworkVitest
+	}
+
+	return frameworkUnknown
+}
+
+// rootDir returns the directory of angular.json or nx.json, or "" outside a
+// workspace
+func (ws *workspace) rootDir() string {
+	if ws == nil {
+		return ""
+	}
+	return ws.root
+}
+
+// projectFor returns the project whose root contains path, or nil
+func (ws *workspace) projectFor(path string) *workspaceProject {
+	if ws == nil {
+		return nil
+	}
+
+	for _, project := range ws.projects {
+		if isWithin(project.root, path) {
+			return project
+		}
+	}
+
+	return nil
+}
+
+// locateComponent searches the project sources for the component file and
+// returns its directory. Matches under preferDir win; an empty string is
+// returned when the component is not found or is ambiguous.
+func (p *workspaceProject) locateComponent(fileName, preferDir string) (string, error) {
+	if p == nil {
+		return "", nil
+	}
+
+	var matches []string
+
+	err := filepath.WalkDir(p.sourceRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if skippedDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.Name() == fileName {
+			matches = append(matches, filepath.Dir(path))
+		}
+		return nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(matches) > 1 {
+		var preferred []string
+		for _, match := range matches {
+			if isWithin(preferDir, match) {
+				preferred = append(preferred, match)
+			}
+		}
+		matches = preferred
+	}
+
+	if len(matches) != 1 {
+		return "", nil
+	}
+
+	return matches[0], nil
+}
+
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// specSettings collects the workspace options that shape the generated spec
+type specSettings struct {
+	framework     testFramework
+	componentType string // file and class suffix, e.g. "component"
+	standalone    bool
+	prefix        string
+	skipTests     bool
+
+	httpTestingModule bool   // use HttpClientTestingModule instead of provideHttpClientTesting
+	mockStore         bool   // provide the NgRx mock store
+	inputsOption      string // render option setting the component's inputs
+
+	format formatOptions
+	config ngSpecConfig
+
+	// mounted is set while rendering the tests of a precondition describe,
+	// whose beforeEach mounts the component into this variable
+	mounted string
+	// language is the language the ACs declare, if any
+	language string
+}
+
+// forAcs returns the settings for rendering an AC document
+func (s specSettings) forAcs(document acDocument) specSettings {
+	if document.Language != "" {
+		s.language = document.Language
+	}
+	return s
+}
+
+func defaultSpecSettings() specSettings {
+	return specSettings{
+		componentType: "component",
+		standalone:    true,
+		mockStore:     true,
+		inputsOption:  "inputs",
+		format:        defaultFormatOptions(),
+	}
+}
+
+// specSettings resolves the settings for a project, falling back to defaults
+func (p *workspaceProject) specSettings() specSettings {
+	settings := defaultSpecSettings()
+	if p == nil {
+		return settings
+	}
+
+	settings.framework = p.framework
+	settings.prefix = p.prefix
+
+	if p.schematic.Type != nil {
+		settings.componentType = strings.ToLower(*p.schematic.Type)
+	}
+	if p.schematic.Standalone != nil {
+		settings.standalone = *p.schematic.Standalone
+	}
+	if p.schematic.SkipTests != nil {
+		settings.skipTests = *p.schematic.SkipTests
+	}
+
+	return settings
+}
+
+// componentFileName returns the component source file name, e.g. user.component.ts
+func (s specSettings) componentFileName(componentPath string) string {
+	if s.componentType == "" {
+		return componentPath + ".ts"
+	}
+	return componentPath + "." + s.componentType + ".ts"
+}
+
+// specFileName returns the spec file name, e.g. user.component.spec.ts
+func (s specSettings) specFileName(componentPath string) string {
+	return strings.TrimSuffix(s.componentFileName(componentPath), ".ts") + ".spec.ts"
+}
+
+// stripPrefix removes the project selector prefix from a component name, so
+// that app-user-profile resolves to user-profile
+func (s specSettings) stripPrefix(componentPath string) string {
+	if s.prefix == "" {
+		return componentPath
+	}
+
+	trimmed := strings.TrimPrefix(componentPath, s.prefix+"-")
+	if trimmed == "" {
+		return componentPath
+	}
+	return trimmed
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetectTestFramework(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   targetConfig
+		expected testFramework
+	}{
+		{"Karma", targetConfig{Builder: "@angular-devkit/build-angular:karma"}, frameworkKarma},
+		{"Karma with new builder", targetConfig{Builder: "@angular/build:karma"}, frameworkKarma},
+		{"Jest builder", targetConfig{Builder: "@angular-builders/jest:run"}, frameworkJest},
+		{"Nx Jest executor", targetConfig{Executor: "@nx/jest:jest"}, frameworkJest},
+		{"Web Test Runner", targetConfig{Builder: "@angular-devkit/build-angular:web-test-runner"}, frameworkWebTestRunner},
+		{"Analog Vitest", targetConfig{Builder: "@analogjs/vitest-angular:test"}, frameworkVitest},
+		{"Nx Vite", targetConfig{Executor: "@nx/vite:test"}, frameworkVitest},
+		{"Unit test builder", targetConfig{Builder: "@angular/build:unit-test"}, frameworkVitest},
+		{"Unknown builder", targetConfig{Builder: "custom:test"}, frameworkUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := detectTestFramework(tt.target)
+			if result != tt.expected {
+				t.Errorf("detectTestFramework(%+v) = %q, want %q", tt.target, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindWorkspaceAngular(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ng-spec-workspace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeFiles(t, tempDir, map[string]string{
+		"angular.json": `{
+			"schematics": {"@schematics/angular:component": {"standalone": false}},
+			"projects": {
+				"shell": {
+					"root": "",
+					"sourceRoot": "src",
+					"prefix": "app",
+					"architect": {"test": {"builder": "@angular-builders/jest:run"}}
+				},
+				"ui": {
+					"root": "projects/ui",
+					"sourceRoot": "projects/ui/src",
+					"prefix": "ui",
+					"schematics": {"@schematics/angular:component": {"type": "widget", "skipTests": true}},
+					"architect": {"test": {"builder": "@angular-devkit/build-angular:karma"}}
+				}
+			}
+		}`,
+		"projects/ui/src/lib/button/button.widget.ts": "",
+	})
+
+	ws, err := findWorkspace(filepath.Join(tempDir, "projects", "ui", "src", "lib"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws == nil {
+		t.Fatal("findWorkspace() returned nil for a directory inside an Angular workspace")
+	}
+
+	project := ws.projectFor(filepath.Join(tempDir, "projects", "ui", "src"))
+	if project == nil || project.name != "ui" {
+		t.Fatalf("projectFor() = %+v, want project ui", project)
+	}
+
+	settings := project.specSettings()
+	expected := defaultSpecSettings()
+	expected.framework = frameworkKarma
+	expected.componentType = "widget"
+	expected.standalone = false
+	expected.prefix = "ui"
+	expected.skipTests = true
+
+	if !reflect.DeepEqual(settings, expected) {
+		t.Errorf("specSettings() = %+v, want %+v", settings, expected)
+	}
+
+	dir, err := project.locateComponent(settings.componentFileName("button"), tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(tempDir, "projects", "ui", "src", "lib", "button"); dir != want {
+		t.Errorf("locateComponent() = %q, want %q", dir, want)
+	}
+
+	shell := ws.projectFor(filepath.Join(tempDir, "src", "app"))
+	if shell == nil || shell.name != "shell" || shell.framework != frameworkJest {
+		t.Errorf("projectFor() = %+v, want project shell using jest", shell)
+	}
+}
+
+func TestFindWorkspaceNx(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ng-spec-nx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	writeFiles(t, tempDir, map[string]string{
+		"nx.json": `{"generators": {"@nx/angular:component": {"type": "component"}}}`,
+		"apps/store/project.json": `{
+			"name": "store",
+			"sourceRoot": "apps/store/src",
+			"prefix": "store",
+			"targets": {"test": {"executor": "@nx/vite:test"}}
+		}`,
+		"node_modules/lib/project.json": `{"name": "ignored"}`,
+	})
+
+	ws, err := findWorkspace(filepath.Join(tempDir, "apps", "store", "src"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ws.projects) != 1 {
+		t.Fatalf("Expected 1 project, got %d", len(ws.projects))
+	}
+
+	project := ws.projectFor(filepath.Join(tempDir, "apps", "store", "src"))
+	if project == nil || project.framework != frameworkVitest || project.prefix != "store" {
+		t.Errorf("projectFor() = %+v, want project store using vitest", project)
+	}
+}
+
+func TestFindWorkspaceOutsideWorkspace(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ng-spec-no-workspace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ws, err := findWorkspace(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if settings := ws.projectFor(tempDir).specSettings(); !reflect.DeepEqual(settings, defaultSpecSettings()) {
+		t.Errorf("specSettings() outside a workspace = %+v, want defaults", settings)
+	}
+}
+
+func TestSpecSettingsFileNames(t *testing.T) {
+	tests := []struct {
+		name          string
+		settings      specSettings
+		componentPath string
+		specFile      string
+	}{
+		{"Default type", defaultSpecSettings(), "user", "user.component.spec.ts"},
+		{"No type", specSettings{}, "user", "user.spec.ts"},
+		{"Prefixed selector", specSettings{componentType: "component", prefix: "app"}, "app-user", "user.component.spec.ts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.settings.specFileName(tt.settings.stripPrefix(tt.componentPath))
+			if result != tt.specFile {
+				t.Errorf("specFileName(%q) = %q, want %q", tt.componentPath, result, tt.specFile)
+			}
+		})
+	}
+}
+
+func TestCreateTemplateWithSettings(t *testing.T) {
+	settings := defaultSpecSettings()
+	settings.framework = frameworkVitest
+	settings.componentType = ""
+	settings.standalone = false
+
+	result := createTemplate("user-card", settings)
+
+	expectedPhrases := []string{
+		"import { describe, expect, it } from 'vitest';",
+		"import { UserCard } from './user-card';",
+		"// TODO: Import the NgModule dependencies of UserCard",
+		"imports: [],",
+	}
+
+	for _, phrase := range expectedPhrases {
+		if !strings.Contains(result, phrase) {
+			t.Errorf("createTemplate() does not contain expected phrase: %q", phrase)
+		}
+	}
+}
@@ -64,7 +64,7 @@ func TestMainIntegration(t *testing.T) {
 			name:            "Default command",
 			args:            []string{},
 			expectedFile:    filepath.Base(tempDir) + ".component.spec.ts",
-			expectedContent: filepath.Base(tempDir) + "Component",
+			expectedContent: "./" + filepath.Base(tempDir) + ".component'",
 		},
 	}
 